@@ -112,3 +112,51 @@ func (r *OpenStackMachineTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) er
 func Convert_v1alpha3_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(in *OpenStackClusterSpec, out *v1alpha4.OpenStackClusterSpec, s conversion.Scope) error {
 	return autoConvert_v1alpha3_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(in, out, s)
 }
+
+// Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus has to be added by us because we added
+// the conditions to the status. They don't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in *v1alpha4.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in, out, s)
+}
+
+// Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec has to be added by us because we added
+// fields to the spec in v1alpha4 which don't exist in v1alpha3, e.g. the failure domain fallback policy.
+func Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(in *v1alpha4.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(in, out, s)
+}
+
+// Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec has to be added by us because we added
+// fields to the spec in v1alpha4 which don't exist in v1alpha3, e.g. instance HA.
+func Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(in *v1alpha4.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(in, out, s)
+}
+
+// Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus has to be added by us because we added
+// the IPv6 APIServerLoadBalancer, the ManagedResources and the conditions to the status. They don't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(in *v1alpha4.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(in, out, s)
+}
+
+// Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume has to be added by us because we added
+// the volume type and the availability zone to the root volume. They don't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(in *v1alpha4.RootVolume, out *RootVolume, s conversion.Scope) error {
+	return autoConvert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(in, out, s)
+}
+
+// Convert_v1alpha4_Instance_To_v1alpha3_Instance has to be added by us because we added
+// the image UUID to the instance. It doesn't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, out *Instance, s conversion.Scope) error {
+	return autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in, out, s)
+}
+
+// Convert_v1alpha4_Network_To_v1alpha3_Network has to be added by us because we added
+// the fixed IP of the instance ports to the network. It doesn't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out *Network, s conversion.Scope) error {
+	return autoConvert_v1alpha4_Network_To_v1alpha3_Network(in, out, s)
+}
+
+// Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam has to be added by us because we added
+// the vNIC type and the binding profile to the network. They don't exist in v1alpha3 so there is nothing to convert.
+func Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(in *v1alpha4.NetworkParam, out *NetworkParam, s conversion.Scope) error {
+	return autoConvert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(in, out, s)
+}
@@ -74,11 +74,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.Instance)(nil), (*Instance)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_Instance_To_v1alpha3_Instance(a.(*v1alpha4.Instance), b.(*Instance), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*LoadBalancer)(nil), (*v1alpha4.LoadBalancer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_LoadBalancer_To_v1alpha4_LoadBalancer(a.(*LoadBalancer), b.(*v1alpha4.LoadBalancer), scope)
 	}); err != nil {
@@ -94,21 +89,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_Network_To_v1alpha3_Network(a.(*v1alpha4.Network), b.(*Network), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkParam)(nil), (*v1alpha4.NetworkParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NetworkParam_To_v1alpha4_NetworkParam(a.(*NetworkParam), b.(*v1alpha4.NetworkParam), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.NetworkParam)(nil), (*NetworkParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(a.(*v1alpha4.NetworkParam), b.(*NetworkParam), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackCluster)(nil), (*v1alpha4.OpenStackCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackCluster_To_v1alpha4_OpenStackCluster(a.(*OpenStackCluster), b.(*v1alpha4.OpenStackCluster), scope)
 	}); err != nil {
@@ -129,21 +114,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.OpenStackClusterSpec)(nil), (*OpenStackClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(a.(*v1alpha4.OpenStackClusterSpec), b.(*OpenStackClusterSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackClusterStatus)(nil), (*v1alpha4.OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(a.(*OpenStackClusterStatus), b.(*v1alpha4.OpenStackClusterStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(a.(*v1alpha4.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachine)(nil), (*v1alpha4.OpenStackMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackMachine_To_v1alpha4_OpenStackMachine(a.(*OpenStackMachine), b.(*v1alpha4.OpenStackMachine), scope)
 	}); err != nil {
@@ -169,21 +144,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(a.(*v1alpha4.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineStatus)(nil), (*v1alpha4.OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(a.(*OpenStackMachineStatus), b.(*v1alpha4.OpenStackMachineStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(a.(*v1alpha4.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineTemplate)(nil), (*v1alpha4.OpenStackMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OpenStackMachineTemplate_To_v1alpha4_OpenStackMachineTemplate(a.(*OpenStackMachineTemplate), b.(*v1alpha4.OpenStackMachineTemplate), scope)
 	}); err != nil {
@@ -229,11 +194,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.RootVolume)(nil), (*RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(a.(*v1alpha4.RootVolume), b.(*RootVolume), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Router)(nil), (*v1alpha4.Router)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Router_To_v1alpha4_Router(a.(*Router), b.(*v1alpha4.Router), scope)
 	}); err != nil {
@@ -329,6 +289,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.Instance)(nil), (*Instance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Instance_To_v1alpha3_Instance(a.(*v1alpha4.Instance), b.(*Instance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Network_To_v1alpha3_Network(a.(*v1alpha4.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.NetworkParam)(nil), (*NetworkParam)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(a.(*v1alpha4.NetworkParam), b.(*NetworkParam), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.OpenStackClusterSpec)(nil), (*OpenStackClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(a.(*v1alpha4.OpenStackClusterSpec), b.(*OpenStackClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(a.(*v1alpha4.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(a.(*v1alpha4.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.OpenStackMachineStatus)(nil), (*OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(a.(*v1alpha4.OpenStackMachineStatus), b.(*OpenStackMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.RootVolume)(nil), (*RootVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(a.(*v1alpha4.RootVolume), b.(*RootVolume), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -442,7 +442,18 @@ func autoConvert_v1alpha3_Instance_To_v1alpha4_Instance(in *Instance, out *v1alp
 	out.Trunk = in.Trunk
 	out.FailureDomain = in.FailureDomain
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
-	out.Networks = (*[]v1alpha4.Network)(unsafe.Pointer(in.Networks))
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = new([]v1alpha4.Network)
+		**out = make([]v1alpha4.Network, len(**in))
+		for i := range **in {
+			if err := Convert_v1alpha3_Network_To_v1alpha4_Network(&(**in)[i], &(**out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Networks = nil
+	}
 	out.Subnet = in.Subnet
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Image = in.Image
@@ -451,7 +462,15 @@ func autoConvert_v1alpha3_Instance_To_v1alpha4_Instance(in *Instance, out *v1alp
 	out.UserData = in.UserData
 	out.Metadata = *(*map[string]string)(unsafe.Pointer(&in.Metadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*v1alpha4.RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(v1alpha4.RootVolume)
+		if err := Convert_v1alpha3_RootVolume_To_v1alpha4_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	out.State = v1alpha4.InstanceState(in.State)
 	out.IP = in.IP
@@ -470,28 +489,51 @@ func autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, o
 	out.Trunk = in.Trunk
 	out.FailureDomain = in.FailureDomain
 	out.SecurityGroups = (*[]string)(unsafe.Pointer(in.SecurityGroups))
-	out.Networks = (*[]Network)(unsafe.Pointer(in.Networks))
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = new([]Network)
+		**out = make([]Network, len(**in))
+		for i := range **in {
+			if err := Convert_v1alpha4_Network_To_v1alpha3_Network(&(**in)[i], &(**out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Networks = nil
+	}
 	out.Subnet = in.Subnet
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Image = in.Image
+	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageFilter requires manual conversion: does not exist in peer-type
 	out.Flavor = in.Flavor
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
 	out.SSHKeyName = in.SSHKeyName
 	out.UserData = in.UserData
 	out.Metadata = *(*map[string]string)(unsafe.Pointer(&in.Metadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolume)
+		if err := Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	out.State = InstanceState(in.State)
 	out.IP = in.IP
+	// WARNING: in.IPv6 requires manual conversion: does not exist in peer-type
 	out.FloatingIP = in.FloatingIP
+	// WARNING: in.Addresses requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hypervisor requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchedAt requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_Instance_To_v1alpha3_Instance is an autogenerated conversion function.
-func Convert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, out *Instance, s conversion.Scope) error {
-	return autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in, out, s)
-}
-
 func autoConvert_v1alpha3_LoadBalancer_To_v1alpha4_LoadBalancer(in *LoadBalancer, out *v1alpha4.LoadBalancer, s conversion.Scope) error {
 	out.Name = in.Name
 	out.ID = in.ID
@@ -538,16 +580,18 @@ func autoConvert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out
 	out.ID = in.ID
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.Subnet = (*Subnet)(unsafe.Pointer(in.Subnet))
+	// WARNING: in.IPv6Subnet requires manual conversion: does not exist in peer-type
 	out.Router = (*Router)(unsafe.Pointer(in.Router))
 	out.APIServerLoadBalancer = (*LoadBalancer)(unsafe.Pointer(in.APIServerLoadBalancer))
+	// WARNING: in.FixedIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.VNICType requires manual conversion: does not exist in peer-type
+	// WARNING: in.Profile requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisablePortSecurity requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedAddressPairs requires manual conversion: does not exist in peer-type
+	// WARNING: in.PortOpts requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_Network_To_v1alpha3_Network is an autogenerated conversion function.
-func Convert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1alpha4_Network_To_v1alpha3_Network(in, out, s)
-}
-
 func autoConvert_v1alpha3_NetworkParam_To_v1alpha4_NetworkParam(in *NetworkParam, out *v1alpha4.NetworkParam, s conversion.Scope) error {
 	out.UUID = in.UUID
 	out.FixedIP = in.FixedIP
@@ -570,14 +614,13 @@ func autoConvert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(in *v1alpha4.Net
 		return err
 	}
 	out.Subnets = *(*[]SubnetParam)(unsafe.Pointer(&in.Subnets))
+	// WARNING: in.VNICType requires manual conversion: does not exist in peer-type
+	// WARNING: in.Profile requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisablePortSecurity requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedAddressPairs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam is an autogenerated conversion function.
-func Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(in *v1alpha4.NetworkParam, out *NetworkParam, s conversion.Scope) error {
-	return autoConvert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackCluster_To_v1alpha4_OpenStackCluster(in *OpenStackCluster, out *v1alpha4.OpenStackCluster, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -677,7 +720,15 @@ func autoConvert_v1alpha3_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(
 		return err
 	}
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
-	out.Bastion = (*v1alpha4.Bastion)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(v1alpha4.Bastion)
+		if err := Convert_v1alpha3_Bastion_To_v1alpha4_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	return nil
 }
 
@@ -685,44 +736,111 @@ func autoConvert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(
 	out.CloudsSecret = (*v1.SecretReference)(unsafe.Pointer(in.CloudsSecret))
 	out.CloudName = in.CloudName
 	out.NodeCIDR = in.NodeCIDR
+	// WARNING: in.NodeIPv6CIDR requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeSubnetPool requires manual conversion: does not exist in peer-type
+	// WARNING: in.IPv6AddressMode requires manual conversion: does not exist in peer-type
+	// WARNING: in.IPv6RAMode requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeSubnetDisableGateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeSubnetDisableDHCP requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha4_Filter_To_v1alpha3_Filter(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha4_SubnetFilter_To_v1alpha3_SubnetFilter(&in.Subnet, &out.Subnet, s); err != nil {
 		return err
 	}
+	// WARNING: in.Router requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableManagedNetworking requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalNetworks requires manual conversion: does not exist in peer-type
 	out.DNSNameservers = *(*[]string)(unsafe.Pointer(&in.DNSNameservers))
+	// WARNING: in.DNSDomain requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkMTU requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProviderNetwork requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeDNSRecords requires manual conversion: does not exist in peer-type
 	out.ExternalRouterIPs = *(*[]ExternalRouterIPParam)(unsafe.Pointer(&in.ExternalRouterIPs))
+	// WARNING: in.RouterAdditionalSubnets requires manual conversion: does not exist in peer-type
 	out.ExternalNetworkID = in.ExternalNetworkID
+	// WARNING: in.ExternalNetwork requires manual conversion: does not exist in peer-type
 	out.ManagedAPIServerLoadBalancer = in.ManagedAPIServerLoadBalancer
+	// WARNING: in.ManagedAPIServerVIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedServerGroups requires manual conversion: does not exist in peer-type
 	out.APIServerFloatingIP = in.APIServerFloatingIP
+	// WARNING: in.DisableAPIServerFloatingIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerFixedIP requires manual conversion: does not exist in peer-type
 	out.APIServerPort = in.APIServerPort
 	out.APIServerLoadBalancerAdditionalPorts = *(*[]int)(unsafe.Pointer(&in.APIServerLoadBalancerAdditionalPorts))
+	// WARNING: in.APIServerLoadBalancerHealthMonitor requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerAdditionalPortsHealthMonitor requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerAdditionalListeners requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerAllowedCIDRs requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerIPv6Subnet requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerID requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerProvider requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancerFlavorID requires manual conversion: does not exist in peer-type
 	out.ManagedSecurityGroups = in.ManagedSecurityGroups
+	// WARNING: in.ManagedSecurityGroupProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.EgressLockdown requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllNodesSecurityGroupRules requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneSecurityGroupRules requires manual conversion: does not exist in peer-type
+	// WARNING: in.WorkerSecurityGroupRules requires manual conversion: does not exist in peer-type
 	out.DisablePortSecurity = in.DisablePortSecurity
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.ComputeTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.LockInstances requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigDrive requires manual conversion: does not exist in peer-type
+	// WARNING: in.Timeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHPublicKey requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha4_APIEndpoint_To_v1alpha3_APIEndpoint(&in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint, s); err != nil {
 		return err
 	}
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
-	out.Bastion = (*Bastion)(unsafe.Pointer(in.Bastion))
+	// WARNING: in.ControlPlaneOmitAvailabilityZones requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomainFallbackPolicy requires manual conversion: does not exist in peer-type
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Bastion)
+		if err := Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	return nil
 }
 
-// Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec is an autogenerated conversion function.
-func Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(in *v1alpha4.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_OpenStackClusterSpec_To_v1alpha3_OpenStackClusterSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in *OpenStackClusterStatus, out *v1alpha4.OpenStackClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
-	out.Network = (*v1alpha4.Network)(unsafe.Pointer(in.Network))
-	out.ExternalNetwork = (*v1alpha4.Network)(unsafe.Pointer(in.ExternalNetwork))
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(v1alpha4.Network)
+		if err := Convert_v1alpha3_Network_To_v1alpha4_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Network = nil
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(v1alpha4.Network)
+		if err := Convert_v1alpha3_Network_To_v1alpha4_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalNetwork = nil
+	}
 	out.FailureDomains = *(*apiv1alpha4.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.ControlPlaneSecurityGroup = (*v1alpha4.SecurityGroup)(unsafe.Pointer(in.ControlPlaneSecurityGroup))
 	out.WorkerSecurityGroup = (*v1alpha4.SecurityGroup)(unsafe.Pointer(in.WorkerSecurityGroup))
 	out.BastionSecurityGroup = (*v1alpha4.SecurityGroup)(unsafe.Pointer(in.BastionSecurityGroup))
-	out.Bastion = (*v1alpha4.Instance)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(v1alpha4.Instance)
+		if err := Convert_v1alpha3_Instance_To_v1alpha4_Instance(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	return nil
 }
 
@@ -733,21 +851,45 @@ func Convert_v1alpha3_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(
 
 func autoConvert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(in *v1alpha4.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
-	out.Network = (*Network)(unsafe.Pointer(in.Network))
-	out.ExternalNetwork = (*Network)(unsafe.Pointer(in.ExternalNetwork))
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(Network)
+		if err := Convert_v1alpha4_Network_To_v1alpha3_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Network = nil
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(Network)
+		if err := Convert_v1alpha4_Network_To_v1alpha3_Network(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalNetwork = nil
+	}
 	out.FailureDomains = *(*apiv1alpha3.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.ControlPlaneSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.ControlPlaneSecurityGroup))
 	out.WorkerSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.WorkerSecurityGroup))
 	out.BastionSecurityGroup = (*SecurityGroup)(unsafe.Pointer(in.BastionSecurityGroup))
-	out.Bastion = (*Instance)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Instance)
+		if err := Convert_v1alpha4_Instance_To_v1alpha3_Instance(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
+	// WARNING: in.APIServerLoadBalancerIPv6 requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerVIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedResources requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHKeyName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(in *v1alpha4.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha4_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackMachine_To_v1alpha4_OpenStackMachine(in *OpenStackMachine, out *v1alpha4.OpenStackMachine, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -782,7 +924,17 @@ func Convert_v1alpha4_OpenStackMachine_To_v1alpha3_OpenStackMachine(in *v1alpha4
 
 func autoConvert_v1alpha3_OpenStackMachineList_To_v1alpha4_OpenStackMachineList(in *OpenStackMachineList, out *v1alpha4.OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_OpenStackMachine_To_v1alpha4_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -793,7 +945,17 @@ func Convert_v1alpha3_OpenStackMachineList_To_v1alpha4_OpenStackMachineList(in *
 
 func autoConvert_v1alpha4_OpenStackMachineList_To_v1alpha3_OpenStackMachineList(in *v1alpha4.OpenStackMachineList, out *OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_OpenStackMachine_To_v1alpha3_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -810,7 +972,17 @@ func autoConvert_v1alpha3_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(
 	out.Flavor = in.Flavor
 	out.Image = in.Image
 	out.SSHKeyName = in.SSHKeyName
-	out.Networks = *(*[]v1alpha4.NetworkParam)(unsafe.Pointer(&in.Networks))
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]v1alpha4.NetworkParam, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_NetworkParam_To_v1alpha4_NetworkParam(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Networks = nil
+	}
 	out.Subnet = in.Subnet
 	out.FloatingIP = in.FloatingIP
 	out.SecurityGroups = *(*[]v1alpha4.SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
@@ -819,7 +991,15 @@ func autoConvert_v1alpha3_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*v1alpha4.RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(v1alpha4.RootVolume)
+		if err := Convert_v1alpha3_RootVolume_To_v1alpha4_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
 	out.ServerGroupID = in.ServerGroupID
 	return nil
 }
@@ -835,27 +1015,60 @@ func autoConvert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(
 	out.CloudsSecret = (*v1.SecretReference)(unsafe.Pointer(in.CloudsSecret))
 	out.CloudName = in.CloudName
 	out.Flavor = in.Flavor
+	// WARNING: in.FlavorID requires manual conversion: does not exist in peer-type
 	out.Image = in.Image
+	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageFilter requires manual conversion: does not exist in peer-type
+	// WARNING: in.RebuildOnImageChange requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResizeOnFlavorChange requires manual conversion: does not exist in peer-type
 	out.SSHKeyName = in.SSHKeyName
-	out.Networks = *(*[]NetworkParam)(unsafe.Pointer(&in.Networks))
+	// WARNING: in.Hostname requires manual conversion: does not exist in peer-type
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]NetworkParam, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_NetworkParam_To_v1alpha3_NetworkParam(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Networks = nil
+	}
+	// WARNING: in.Ports requires manual conversion: does not exist in peer-type
 	out.Subnet = in.Subnet
 	out.FloatingIP = in.FloatingIP
+	// WARNING: in.AssociateFloatingIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.FloatingIPPool requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadBalancerMember requires manual conversion: does not exist in peer-type
 	out.SecurityGroups = *(*[]SecurityGroupParam)(unsafe.Pointer(&in.SecurityGroups))
 	out.UserDataSecret = (*v1.SecretReference)(unsafe.Pointer(in.UserDataSecret))
 	out.Trunk = in.Trunk
+	// WARNING: in.Subports requires manual conversion: does not exist in peer-type
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ServerMetadata = *(*map[string]string)(unsafe.Pointer(&in.ServerMetadata))
 	out.ConfigDrive = (*bool)(unsafe.Pointer(in.ConfigDrive))
-	out.RootVolume = (*RootVolume)(unsafe.Pointer(in.RootVolume))
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolume)
+		if err := Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RootVolume = nil
+	}
+	// WARNING: in.AdditionalBlockDevices requires manual conversion: does not exist in peer-type
 	out.ServerGroupID = in.ServerGroupID
+	// WARNING: in.ServerGroupName requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServerGroupPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceHA requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapCheck requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceCreateRetries requires manual conversion: does not exist in peer-type
+	// WARNING: in.StopBeforeDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.ShelveOnDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.AccessIPFamily requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec is an autogenerated conversion function.
-func Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(in *v1alpha4.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackMachineStatus_To_v1alpha4_OpenStackMachineStatus(in *OpenStackMachineStatus, out *v1alpha4.OpenStackMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
@@ -874,16 +1087,19 @@ func autoConvert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineSta
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.ServerGroupID requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceCreateRetryCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostID requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hypervisor requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchedAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.FloatingIPID requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus is an autogenerated conversion function.
-func Convert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in *v1alpha4.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha4_OpenStackMachineStatus_To_v1alpha3_OpenStackMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_OpenStackMachineTemplate_To_v1alpha4_OpenStackMachineTemplate(in *OpenStackMachineTemplate, out *v1alpha4.OpenStackMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_OpenStackMachineTemplateSpec_To_v1alpha4_OpenStackMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -912,7 +1128,17 @@ func Convert_v1alpha4_OpenStackMachineTemplate_To_v1alpha3_OpenStackMachineTempl
 
 func autoConvert_v1alpha3_OpenStackMachineTemplateList_To_v1alpha4_OpenStackMachineTemplateList(in *OpenStackMachineTemplateList, out *v1alpha4.OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_OpenStackMachineTemplate_To_v1alpha4_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -923,7 +1149,17 @@ func Convert_v1alpha3_OpenStackMachineTemplateList_To_v1alpha4_OpenStackMachineT
 
 func autoConvert_v1alpha4_OpenStackMachineTemplateList_To_v1alpha3_OpenStackMachineTemplateList(in *v1alpha4.OpenStackMachineTemplateList, out *OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_OpenStackMachineTemplate_To_v1alpha3_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -998,14 +1234,13 @@ func autoConvert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(in *v1alpha4.RootVol
 	out.SourceUUID = in.SourceUUID
 	out.DeviceType = in.DeviceType
 	out.Size = in.Size
+	// WARNING: in.VolumeType requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeTypesByAvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.PreserveOnDelete requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume is an autogenerated conversion function.
-func Convert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(in *v1alpha4.RootVolume, out *RootVolume, s conversion.Scope) error {
-	return autoConvert_v1alpha4_RootVolume_To_v1alpha3_RootVolume(in, out, s)
-}
-
 func autoConvert_v1alpha3_Router_To_v1alpha4_Router(in *Router, out *v1alpha4.Router, s conversion.Scope) error {
 	out.Name = in.Name
 	out.ID = in.ID
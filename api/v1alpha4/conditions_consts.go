@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+const (
+	// InstanceReadyCondition reports on current status of the OpenStack instance. Ready indicates the instance is in a Running state.
+	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
+
+	// InstanceNotReadyReason used when the instance is in a pending state.
+	InstanceNotReadyReason = "InstanceNotReady"
+	// InstanceStateErrorReason used when the instance is in an unexpected state.
+	InstanceStateErrorReason = "InstanceStateError"
+	// ComputeHostDownReason used when the compute service of the hypervisor hosting the instance is down.
+	ComputeHostDownReason = "ComputeHostDown"
+	// InstanceDeletedReason used when the instance has been deleted outside of Kubernetes.
+	InstanceDeletedReason = "InstanceDeleted"
+	// InstanceHARecoveryFailedReason used when Masakari failed to recover the instance.
+	InstanceHARecoveryFailedReason = "InstanceHARecoveryFailed"
+	// InstanceCreateRetryReason used when the instance went into ERROR state while it was created and is created again.
+	InstanceCreateRetryReason = "InstanceCreateRetry"
+)
+
+const (
+	// BootstrapSucceededCondition reports whether the bootstrap check of the instance passed.
+	BootstrapSucceededCondition clusterv1.ConditionType = "BootstrapSucceeded"
+
+	// WaitingForBootstrapReason used when the bootstrap check of the instance didn't pass yet.
+	WaitingForBootstrapReason = "WaitingForBootstrap"
+	// BootstrapTimeoutReason used when the bootstrap check of the instance didn't pass in time.
+	BootstrapTimeoutReason = "BootstrapTimeout"
+)
+
+const (
+	// InfrastructureCompatibleCondition reports whether the OpenStack cloud provides all services and extensions required by the spec.
+	InfrastructureCompatibleCondition clusterv1.ConditionType = "InfrastructureCompatible"
+
+	// MissingCapabilitiesReason used when the cloud lacks services or extensions required by the spec.
+	MissingCapabilitiesReason = "MissingCapabilities"
+)
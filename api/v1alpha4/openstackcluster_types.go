@@ -44,42 +44,226 @@ type OpenStackClusterSpec struct {
 	// If you leave this empty, no network will be created.
 	NodeCIDR string `json:"nodeCidr,omitempty"`
 
+	// NodeIPv6CIDR is an IPv6 subnet which is created in the network of the cluster
+	// in addition to the IPv4 subnet of NodeCIDR or NodeSubnetPool, so that the
+	// cluster is dual-stack. The subnet is connected to the router of the cluster,
+	// and IPv6AddressMode and IPv6RAMode apply to it.
+	// +optional
+	NodeIPv6CIDR string `json:"nodeIPv6Cidr,omitempty"`
+
+	// NodeSubnetPool selects a Neutron subnet pool from which the CIDR of the subnet
+	// is allocated instead of NodeCIDR. Cluster actuator will create a network, a subnet
+	// with a CIDR of the subnet pool, and a router connected to this subnet.
+	// Mutually exclusive with NodeCIDR.
+	// +optional
+	NodeSubnetPool *SubnetPool `json:"nodeSubnetPool,omitempty"`
+
+	// IPv6AddressMode is the ipv6_address_mode of the subnet created for NodeCIDR or
+	// NodeSubnetPool, which selects how the machines get their addresses. Only valid
+	// for an IPv6 subnet.
+	// +kubebuilder:validation:Enum=slaac;dhcpv6-stateful;dhcpv6-stateless
+	// +optional
+	IPv6AddressMode string `json:"ipv6AddressMode,omitempty"`
+
+	// IPv6RAMode is the ipv6_ra_mode of the subnet created for NodeCIDR or
+	// NodeSubnetPool, which selects how router advertisements are sent. Only valid
+	// for an IPv6 subnet.
+	// +kubebuilder:validation:Enum=slaac;dhcpv6-stateful;dhcpv6-stateless
+	// +optional
+	IPv6RAMode string `json:"ipv6RaMode,omitempty"`
+
+	// NodeSubnetDisableGateway creates the subnet for NodeCIDR or NodeSubnetPool
+	// without a gateway. The subnet is then not connected to the router of the
+	// cluster, so the routing of the nodes has to be provided otherwise.
+	// +optional
+	NodeSubnetDisableGateway bool `json:"nodeSubnetDisableGateway,omitempty"`
+
+	// NodeSubnetDisableDHCP creates the subnet for NodeCIDR or NodeSubnetPool
+	// with DHCP disabled.
+	// +optional
+	NodeSubnetDisableDHCP bool `json:"nodeSubnetDisableDHCP,omitempty"`
+
 	// If NodeCIDR cannot be set this can be used to detect an existing network.
 	Network Filter `json:"network,omitempty"`
 
 	// If NodeCIDR cannot be set this can be used to detect an existing subnet.
 	Subnet SubnetFilter `json:"subnet,omitempty"`
 
+	// If NodeCIDR cannot be set this can be used to detect an existing router,
+	// which is then reported in the status of the cluster.
+	// +optional
+	Router *RouterFilter `json:"router,omitempty"`
+
+	// DisableManagedNetworking uses the existing network, subnet and router
+	// selected by Network, Subnet and Router, and creates no networking resources
+	// at all. No external network is looked up and no floating IP is created, so
+	// the ControlPlaneEndpoint has to be set. ManagedSecurityGroups and
+	// ManagedAPIServerLoadBalancer cannot be used, and NodeCIDR and NodeSubnetPool
+	// have to be unset.
+	// +optional
+	DisableManagedNetworking bool `json:"disableManagedNetworking,omitempty"`
+
+	// AdditionalNetworks are attached to the machines of the cluster in addition
+	// to the networks of the machines, e.g. storage or backup networks.
+	// +optional
+	AdditionalNetworks []AdditionalNetwork `json:"additionalNetworks,omitempty"`
+
 	// DNSNameservers is the list of nameservers for OpenStack Subnet being created.
 	// Set this value when you need create a new network/subnet while the access
 	// through DNS is required.
 	DNSNameservers []string `json:"dnsNameservers,omitempty"`
+	// DNSDomain is the dns_domain of the OpenStack Network being created. With the
+	// DNS integration of Neutron, the ports of the machines publish records under
+	// this domain, e.g. in a Designate zone of the cluster. It has to end with a dot.
+	// +kubebuilder:validation:Pattern=`^.*\.$`
+	// +optional
+	DNSDomain string `json:"dnsDomain,omitempty"`
+	// NetworkMTU is the MTU of the OpenStack Network being created, e.g. 9000 for
+	// jumbo frames. If unset, the default MTU of Neutron is used.
+	// +kubebuilder:validation:Minimum=68
+	// +optional
+	NetworkMTU int `json:"networkMTU,omitempty"`
+	// ProviderNetwork creates the OpenStack Network being created as a provider
+	// network, e.g. a VLAN on a physical network, for clouds which do not allow
+	// tenant overlay networks. Creating provider networks requires the admin role
+	// by default.
+	// +optional
+	ProviderNetwork *ProviderNetwork `json:"providerNetwork,omitempty"`
+	// NodeDNSRecords creates records in a Designate zone for the addresses of each
+	// machine, so that the nodes are addressable by name. The records are removed
+	// when the machine is deleted.
+	// +optional
+	NodeDNSRecords *NodeDNSRecords `json:"nodeDNSRecords,omitempty"`
 	// ExternalRouterIPs is an array of externalIPs on the respective subnets.
 	// This is necessary if the router needs a fixed ip in a specific subnet.
+	// Subnets selected by a filter without a network are looked up in the
+	// external network.
 	ExternalRouterIPs []ExternalRouterIPParam `json:"externalRouterIPs,omitempty"`
+	// RouterAdditionalSubnets are existing subnets, e.g. of a services network
+	// for storage or databases, which are attached to the router of the cluster
+	// in addition to the subnets of the cluster network. The interfaces are
+	// removed when the router is deleted.
+	// +optional
+	RouterAdditionalSubnets []SubnetParam `json:"routerAdditionalSubnets,omitempty"`
 	// ExternalNetworkID is the ID of an external OpenStack Network. This is necessary
 	// to get public internet to the VMs.
 	// +optional
 	ExternalNetworkID string `json:"externalNetworkId,omitempty"`
 
+	// ExternalNetwork selects the external network by a filter, e.g. by name or
+	// tags, if ExternalNetworkID is not set. The filter has to match exactly one
+	// external network.
+	// +optional
+	ExternalNetwork *Filter `json:"externalNetwork,omitempty"`
+
 	// ManagedAPIServerLoadBalancer defines whether a LoadBalancer for the
 	// APIServer should be created. If set to true the following properties are
 	// mandatory: APIServerFloatingIP, APIServerPort
 	// +optional
 	ManagedAPIServerLoadBalancer bool `json:"managedAPIServerLoadBalancer"`
 
-	// APIServerFloatingIP is the floatingIP which will be associated
-	// to the APIServer. The floatingIP will be created if it not
-	// already exists.
+	// ManagedAPIServerVIP defines whether a port reserving the address of the
+	// APIServer should be created on the network of the cluster instead of a
+	// load balancer, e.g. for clouds without Octavia. The address, which is
+	// APIServerFixedIP if set, is added to the allowed address pairs of the
+	// control plane machines, so that kube-vip or keepalived can own it. It
+	// cannot be used together with ManagedAPIServerLoadBalancer.
+	// +optional
+	ManagedAPIServerVIP bool `json:"managedAPIServerVIP,omitempty"`
+
+	// ManagedServerGroups defines whether a server group with the
+	// soft-anti-affinity policy should be created for the control plane and for
+	// each machine deployment, so that their machines are spread across
+	// hypervisors. It is only used for machines without a server group in
+	// their spec.
+	// +optional
+	ManagedServerGroups bool `json:"managedServerGroups,omitempty"`
+
+	// APIServerFloatingIP is an existing floatingIP which will be associated
+	// to the APIServer, e.g. an address DNS records already point to. It is
+	// never allocated nor deleted by the controller.
 	APIServerFloatingIP string `json:"apiServerFloatingIP,omitempty"`
 
+	// DisableAPIServerFloatingIP associates no floating IP with the API server,
+	// for clusters which are only reachable on internal networks. The
+	// ControlPlaneEndpoint is then the VIP of the APIServerLoadBalancer, or
+	// APIServerFixedIP without a load balancer.
+	// +optional
+	DisableAPIServerFloatingIP bool `json:"disableAPIServerFloatingIP,omitempty"`
+
+	// APIServerFixedIP is the address of the API server on the cluster network
+	// with DisableAPIServerFloatingIP. With ManagedAPIServerLoadBalancer, it is
+	// the VIP of the load balancer. Without, it has to be provided by the control
+	// plane machines, e.g. with kube-vip.
+	// +optional
+	APIServerFixedIP string `json:"apiServerFixedIP,omitempty"`
+
 	// APIServerPort is the port on which the listener on the APIServer
 	// will be created
 	APIServerPort int `json:"apiServerPort,omitempty"`
 
-	// APIServerLoadBalancerAdditionalPorts adds additional ports to the APIServerLoadBalancer
+	// APIServerLoadBalancerAdditionalPorts adds additional ports to the APIServerLoadBalancer,
+	// e.g. for konnectivity. They are opened in the managed security group of the
+	// control plane, and their listeners are deleted when they are removed.
 	APIServerLoadBalancerAdditionalPorts []int `json:"apiServerLoadBalancerAdditionalPorts,omitempty"`
 
+	// APIServerLoadBalancerHealthMonitor configures the health monitor of the APIServer
+	// port of the APIServerLoadBalancer, e.g. its timing for slow control planes. If
+	// unset, a TCP connection to the APIServer port of the member is checked.
+	// +optional
+	APIServerLoadBalancerHealthMonitor *LoadBalancerHealthMonitor `json:"apiServerLoadBalancerHealthMonitor,omitempty"`
+
+	// APIServerLoadBalancerAdditionalPortsHealthMonitor configures the health monitors of the
+	// APIServerLoadBalancerAdditionalPorts. If unset, a TCP connection to the port of the member is checked.
+	// +optional
+	APIServerLoadBalancerAdditionalPortsHealthMonitor *LoadBalancerHealthMonitor `json:"apiServerLoadBalancerAdditionalPortsHealthMonitor,omitempty"`
+
+	// APIServerLoadBalancerAdditionalListeners adds listeners with another protocol
+	// than TCP or another port of the members to the APIServerLoadBalancer, e.g. to
+	// front NodePort services. Their pools are checked with a TCP connection, or
+	// with UDP-CONNECT for UDP.
+	// +optional
+	APIServerLoadBalancerAdditionalListeners []LoadBalancerListener `json:"apiServerLoadBalancerAdditionalListeners,omitempty"`
+
+	// APIServerAllowedCIDRs restricts the access to the APIServer to the given CIDRs or
+	// addresses. The listener of the APIServer port of the APIServerLoadBalancer only
+	// accepts traffic from them, from the subnet of its VIP and from the external addresses
+	// of the router of the cluster. The managed security group of the control plane only
+	// accepts traffic to the APIServer from them, from the subnets of the cluster network
+	// and from the machines of the cluster. If unset, the APIServer is reachable from
+	// everywhere.
+	// +optional
+	APIServerAllowedCIDRs []string `json:"apiServerAllowedCidrs,omitempty"`
+
+	// APIServerLoadBalancerIPv6Subnet selects an IPv6 subnet of the cluster network. If set,
+	// a second APIServerLoadBalancer with a VIP in this subnet is created, so that IPv6 clients
+	// can reach the APIServer directly in dual-stack deployments. The control plane machines
+	// must have an address in this subnet.
+	// +optional
+	APIServerLoadBalancerIPv6Subnet *SubnetFilter `json:"apiServerLoadBalancerIPv6Subnet,omitempty"`
+
+	// APIServerLoadBalancerID is the ID of an existing Octavia load balancer which
+	// is used as APIServerLoadBalancer with ManagedAPIServerLoadBalancer. The
+	// listeners, pools and members of the cluster are created on it, but the load
+	// balancer itself is never created nor deleted, and its VIP subnet has to
+	// route to the control plane machines.
+	// +optional
+	APIServerLoadBalancerID string `json:"apiServerLoadBalancerID,omitempty"`
+
+	// APIServerLoadBalancerProvider is the Octavia provider of the APIServerLoadBalancer,
+	// e.g. amphora or ovn. The pools of the ovn provider balance by source IP and
+	// port. If unset, the default provider of the cloud is used. It cannot be
+	// changed once the load balancer is created.
+	// +optional
+	APIServerLoadBalancerProvider string `json:"apiServerLoadBalancerProvider,omitempty"`
+
+	// APIServerLoadBalancerFlavorID is the ID of the Octavia flavor of the
+	// APIServerLoadBalancer, e.g. one with active-standby amphorae. If unset, the
+	// default flavor of the cloud is used. It cannot be changed once the load
+	// balancer is created.
+	// +optional
+	APIServerLoadBalancerFlavorID string `json:"apiServerLoadBalancerFlavorID,omitempty"`
+
 	// ManagedSecurityGroups defines that kubernetes manages the OpenStack security groups
 	// for now, that means that we'll create security group allows traffic to/from
 	// machines belonging to that group based on Calico CNI plugin default network
@@ -88,6 +272,33 @@ type OpenStackClusterSpec struct {
 	// +optional
 	ManagedSecurityGroups bool `json:"managedSecurityGroups"`
 
+	// ManagedSecurityGroupProfile selects the rules for the traffic of the CNI between
+	// the nodes in the managed security groups. If unset, the rules of Calico are used.
+	// +optional
+	ManagedSecurityGroupProfile ManagedSecurityGroupProfile `json:"managedSecurityGroupProfile,omitempty"`
+
+	// EgressLockdown replaces the rules of the managed security groups which allow all
+	// egress traffic with rules which only allow traffic between the machines of the
+	// cluster, to the API server endpoint, to the metadata service and to the given CIDRs.
+	// +optional
+	EgressLockdown *EgressLockdown `json:"egressLockdown,omitempty"`
+
+	// AllNodesSecurityGroupRules are added to the managed security groups of the
+	// control plane and of the workers, e.g. to allow VXLAN traffic of the CNI
+	// between all nodes.
+	// +optional
+	AllNodesSecurityGroupRules []SecurityGroupRuleSpec `json:"allNodesSecurityGroupRules,omitempty"`
+
+	// ControlPlaneSecurityGroupRules are added to the managed security group of
+	// the control plane.
+	// +optional
+	ControlPlaneSecurityGroupRules []SecurityGroupRuleSpec `json:"controlPlaneSecurityGroupRules,omitempty"`
+
+	// WorkerSecurityGroupRules are added to the managed security group of the
+	// workers, e.g. to open the NodePort range to an office network.
+	// +optional
+	WorkerSecurityGroupRules []SecurityGroupRuleSpec `json:"workerSecurityGroupRules,omitempty"`
+
 	// DisablePortSecurity disables the port security of the network created for the
 	// Kubernetes cluster, which also disables SecurityGroups
 	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
@@ -95,6 +306,39 @@ type OpenStackClusterSpec struct {
 	// Tags for all resources in cluster
 	Tags []string `json:"tags,omitempty"`
 
+	// ComputeTags are added to Tags for the servers of the machines, for clouds which
+	// enforce different tag policies for Nova than for Neutron.
+	// +optional
+	ComputeTags []string `json:"computeTags,omitempty"`
+
+	// NetworkTags are added to Tags for the Neutron resources of the cluster, i.e. the
+	// network, subnet and router, and the trunks of the machines.
+	// +optional
+	NetworkTags []string `json:"networkTags,omitempty"`
+
+	// LockInstances locks the instances of the machines and the bastion after they are
+	// created, so that they can't be deleted outside of Cluster API by accident, e.g. in
+	// Horizon. The instances are unlocked before they are deleted.
+	// +optional
+	LockInstances bool `json:"lockInstances,omitempty"`
+
+	// ConfigDrive is the default of ConfigDrive of the machines and the bastion,
+	// e.g. for clouds without a metadata service on provider networks. The
+	// ConfigDrive of a machine takes precedence.
+	// +optional
+	ConfigDrive *bool `json:"configDrive,omitempty"`
+
+	// Timeouts overrides the timeouts of the operations on the instances of the
+	// cluster, e.g. for clouds which take long to create servers.
+	// +optional
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+
+	// SSHPublicKey is an SSH public key for which a keypair is created in Nova
+	// for the cluster. Machines and the bastion without an SSHKeyName use this
+	// keypair. It is deleted together with the cluster.
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
@@ -102,6 +346,20 @@ type OpenStackClusterSpec struct {
 	// ControlPlaneAvailabilityZones is the az to deploy control plane to
 	ControlPlaneAvailabilityZones []string `json:"controlPlaneAvailabilityZones,omitempty"`
 
+	// ControlPlaneOmitAvailabilityZones are availability zones which are never used
+	// for the control plane, e.g. edge sites. They are still published as failure
+	// domains for the worker machines.
+	// +optional
+	ControlPlaneOmitAvailabilityZones []string `json:"controlPlaneOmitAvailabilityZones,omitempty"`
+
+	// FailureDomainFallbackPolicy defines what happens to machines whose failure domain
+	// refers to an unavailable availability zone. With None, the default, the machine is
+	// retried in its failure domain. With AnyAvailable, unavailable availability zones are
+	// not reported as failure domains and machines are created in another available failure domain.
+	// +kubebuilder:validation:Enum=None;AnyAvailable
+	// +optional
+	FailureDomainFallbackPolicy FailureDomainFallbackPolicy `json:"failureDomainFallbackPolicy,omitempty"`
+
 	// Bastion is the OpenStack instance to login the nodes
 	//+optional
 	Bastion *Bastion `json:"bastion,omitempty"`
@@ -133,6 +391,27 @@ type OpenStackClusterStatus struct {
 	BastionSecurityGroup *SecurityGroup `json:"bastionSecurityGroup,omitempty"`
 
 	Bastion *Instance `json:"bastion,omitempty"`
+
+	// APIServerLoadBalancerIPv6 contains the information about the IPv6 APIServerLoadBalancer,
+	// if APIServerLoadBalancerIPv6Subnet is set.
+	APIServerLoadBalancerIPv6 *LoadBalancer `json:"apiServerLoadBalancerIPv6,omitempty"`
+
+	// APIServerVIP contains the information about the port reserving the
+	// address of the APIServer, if ManagedAPIServerVIP is set.
+	APIServerVIP *APIServerVIP `json:"apiServerVIP,omitempty"`
+
+	// ManagedResources contains the IDs of the OpenStack resources which were created
+	// by the cluster controller and are deleted together with the cluster.
+	ManagedResources *ManagedResources `json:"managedResources,omitempty"`
+
+	// SSHKeyName is the name of the Nova keypair created from the SSHPublicKey
+	// of the cluster.
+	// +optional
+	SSHKeyName string `json:"sshKeyName,omitempty"`
+
+	// Conditions defines current service state of the OpenStackCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -164,6 +443,16 @@ type OpenStackClusterList struct {
 	Items           []OpenStackCluster `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the OpenStackCluster resource.
+func (r *OpenStackCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackCluster to the predescribed clusterv1.Conditions.
+func (r *OpenStackCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenStackCluster{}, &OpenStackClusterList{})
 }
@@ -19,6 +19,7 @@ package v1alpha4
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
@@ -33,7 +34,9 @@ type OpenStackMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
 	ProviderID *string `json:"providerID,omitempty"`
 
-	// InstanceID is the OpenStack instance ID for this machine.
+	// InstanceID is the OpenStack instance ID for this machine. If it is set
+	// when the machine is created, the existing server with this ID is adopted
+	// instead of creating a new one.
 	InstanceID *string `json:"instanceID,omitempty"`
 
 	// The name of the secret containing the openstack credentials
@@ -45,26 +48,101 @@ type OpenStackMachineSpec struct {
 	CloudName string `json:"cloudName"`
 
 	// The flavor reference for the flavor for your server instance.
-	Flavor string `json:"flavor"`
+	// +optional
+	Flavor string `json:"flavor,omitempty"`
+
+	// FlavorID is the ID of the flavor for your server instance. It is used
+	// instead of looking up the flavor by name, e.g. if several flavors have
+	// the same name. Mutually exclusive with Flavor.
+	// +optional
+	FlavorID string `json:"flavorID,omitempty"`
 
 	// The name of the image to use for your server instance.
 	// If the RootVolume is specified, this will be ignored and use rootVolume directly.
-	Image string `json:"image"`
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ImageUUID is the ID of the image to use for your server instance. It is
+	// used instead of looking up the image by name, e.g. if several images have
+	// the same name. Mutually exclusive with Image and ImageFilter.
+	// +optional
+	ImageUUID string `json:"imageUUID,omitempty"`
+
+	// ImageFilter selects the image to use for your server instance by its
+	// attributes, e.g. the most recent image with a tag and an os_version
+	// property. Mutually exclusive with Image and ImageUUID.
+	// +optional
+	ImageFilter *ImageFilter `json:"imageFilter,omitempty"`
+
+	// RebuildOnImageChange allows to change the image of the machine, which
+	// rebuilds its server in place with the new image instead of replacing
+	// the machine, e.g. for bare metal servers of Ironic. The server keeps its
+	// ports and boots with its original user data again. Servers which boot
+	// from a volume can't be rebuilt.
+	// +optional
+	RebuildOnImageChange bool `json:"rebuildOnImageChange,omitempty"`
+
+	// ResizeOnFlavorChange allows to change the flavor of the machine, which
+	// resizes its server to the new flavor instead of replacing the machine.
+	// The resize is confirmed right away. It requires the controller to run
+	// with --enable-instance-resize, otherwise the flavor change is ignored.
+	// +optional
+	ResizeOnFlavorChange bool `json:"resizeOnFlavorChange,omitempty"`
 
 	// The ssh key to inject in the instance
 	SSHKeyName string `json:"sshKeyName,omitempty"`
 
+	// Hostname is a template of the name of the server, which Nova derives
+	// the hostname of the instance from, e.g. {machine}.{cluster}.example.com.
+	// {machine} is replaced by the name of the machine, {cluster} by the name
+	// of the cluster and {namespace} by the namespace. The first label of the
+	// name is set as dns_name of the primary port of the server, which
+	// requires the DNS integration of Neutron. If unset, the server is named
+	// after the machine.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
 	// A networks object. Required parameter when there are multiple networks defined for the tenant.
 	// When you do not specify the networks parameter, the server attaches to the only network created for the current tenant.
+	// The ports are attached in the order of the networks, followed by the additional networks of the
+	// cluster, which determines the order of the interfaces in the guest, e.g. eth0 and eth1. A filter
+	// matching several networks or subnets adds their ports ordered by name and ID, unless it has a sortKey.
 	Networks []NetworkParam `json:"networks,omitempty"`
 
-	// UUID, IP address of a port from this subnet will be marked as AccessIPv4 on the created compute instance
+	// Ports defines the ports of the machine. If set, a port is created for
+	// each entry instead of a port for each network of Networks. The ports
+	// are attached in the order of the entries, which determines the order
+	// of the interfaces in the guest, and are tagged with
+	// capo-port-index=<index>.
+	// +optional
+	Ports []PortOpts `json:"ports,omitempty"`
+
+	// UUID, IP address of a port from this subnet will be marked as AccessIPv4, or as AccessIPv6 for an IPv6 subnet, on the created compute instance.
+	// It takes precedence over the primary port.
 	Subnet string `json:"subnet,omitempty"`
 
-	// The floatingIP which will be associated to the machine, only used for master.
+	// The floatingIP which will be associated to the machine, only used for master,
+	// or with AssociateFloatingIP.
 	// The floatingIP should have been created and haven't been associated.
 	FloatingIP string `json:"floatingIP,omitempty"`
 
+	// AssociateFloatingIP associates a floating IP with the machine. FloatingIP
+	// is used if set, otherwise a floating IP is allocated from FloatingIPPool,
+	// which is released when the machine is deleted.
+	// +optional
+	AssociateFloatingIP bool `json:"associateFloatingIP,omitempty"`
+
+	// FloatingIPPool is the name or ID of the external network the floating IP
+	// of AssociateFloatingIP is allocated from. Defaults to the external network
+	// of the cluster.
+	// +optional
+	FloatingIPPool string `json:"floatingIPPool,omitempty"`
+
+	// LoadBalancerMember configures the members of a control plane machine in
+	// the pools of the APIServerLoadBalancer. It is updated on existing members.
+	// +optional
+	LoadBalancerMember *LoadBalancerMember `json:"loadBalancerMember,omitempty"`
+
 	// The names of the security groups to assign to the instance
 	SecurityGroups []SecurityGroupParam `json:"securityGroups,omitempty"`
 
@@ -74,6 +152,11 @@ type OpenStackMachineSpec struct {
 	// Whether the server instance is created on a trunk port or not.
 	Trunk bool `json:"trunk,omitempty"`
 
+	// Subports are added to the trunk of the first port of the machine, e.g.
+	// for VLAN-aware workloads. They require a trunk on the first port.
+	// +optional
+	Subports []Subport `json:"subports,omitempty"`
+
 	// Machine tags
 	// Requires Nova api 2.52 minimum!
 	Tags []string `json:"tags,omitempty"`
@@ -82,13 +165,71 @@ type OpenStackMachineSpec struct {
 	ServerMetadata map[string]string `json:"serverMetadata,omitempty"`
 
 	// Config Drive support
+	// If unset, the config drive is enabled for servers with a port on a subnet without DHCP.
 	ConfigDrive *bool `json:"configDrive,omitempty"`
 
 	// The volume metadata to boot from
 	RootVolume *RootVolume `json:"rootVolume,omitempty"`
 
+	// AdditionalBlockDevices are Cinder volumes which are created together with
+	// the instance and attached to it in addition to the root disk, e.g. a
+	// dedicated disk for etcd. They are deleted together with the instance.
+	// +optional
+	AdditionalBlockDevices []AdditionalBlockDevice `json:"additionalBlockDevices,omitempty"`
+
 	// The server group to assign the machine to
 	ServerGroupID string `json:"serverGroupID,omitempty"`
+
+	// The name of the server group to assign the machine to. The name must be
+	// unique in the project. Mutually exclusive with ServerGroupID.
+	ServerGroupName string `json:"serverGroupName,omitempty"`
+
+	// ServerGroupPolicy is the policy of the server group which is managed for
+	// the machine deployment of the machine, or for the control plane. Setting
+	// it enables the managed server group of the machine, even if
+	// ManagedServerGroups isn't set on the OpenStackCluster, whose policy is
+	// soft-anti-affinity. Ignored if ServerGroupID or ServerGroupName is set.
+	// +optional
+	ServerGroupPolicy ServerGroupPolicy `json:"serverGroupPolicy,omitempty"`
+
+	// InstanceHA marks the server as protected by Masakari instance high availability.
+	// If Masakari fails to recover the server, the machine is marked as failed so it can be remediated.
+	// +optional
+	InstanceHA bool `json:"instanceHA,omitempty"`
+
+	// BootstrapCheck delays the machine becoming ready until the bootstrap of the
+	// instance succeeded. If unset, the machine is ready once the instance is active.
+	// +optional
+	BootstrapCheck *BootstrapCheck `json:"bootstrapCheck,omitempty"`
+
+	// InstanceCreateRetries is the number of times a server which goes into
+	// ERROR state while it is created, e.g. because no valid host was found, is
+	// deleted and created again before the machine is marked as failed. The
+	// delay between the retries doubles with each retry, up to 10 minutes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	InstanceCreateRetries int `json:"instanceCreateRetries,omitempty"`
+
+	// StopBeforeDelete stops the server and waits until it is shut off before
+	// it is deleted, so that the guest OS can shut down cleanly and flush its
+	// disks.
+	// +optional
+	StopBeforeDelete bool `json:"stopBeforeDelete,omitempty"`
+
+	// ShelveOnDelete shelves the instance instead of deleting it when the
+	// machine is deleted, so that its root disk is kept, e.g. for the forensics
+	// of a failed control plane machine.
+	// +optional
+	ShelveOnDelete *ShelveOnDelete `json:"shelveOnDelete,omitempty"`
+
+	// AccessIPFamily is the IP family of the address which is used to access
+	// the instance, e.g. as load balancer member or for the bootstrap check.
+	// If it is IPv6, the first internal IPv6 address of the instance is used.
+	// If unset, the IPv4 address is used, or the IPv6 address if the instance
+	// has no IPv4 address.
+	// +optional
+	AccessIPFamily IPFamily `json:"accessIPFamily,omitempty"`
 }
 
 // OpenStackMachineStatus defines the observed state of OpenStackMachine.
@@ -105,6 +246,39 @@ type OpenStackMachineStatus struct {
 	// +optional
 	InstanceState *InstanceState `json:"instanceState,omitempty"`
 
+	// ServerGroupID is the ID of the managed server group the instance was
+	// assigned to, if ManagedServerGroups is enabled on the OpenStackCluster.
+	// +optional
+	ServerGroupID string `json:"serverGroupID,omitempty"`
+
+	// InstanceCreateRetryCount is the number of times the server was created
+	// again after it went into ERROR state.
+	// +optional
+	InstanceCreateRetryCount int `json:"instanceCreateRetryCount,omitempty"`
+
+	// AvailabilityZone is the availability zone the instance runs in.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// HostID identifies the compute host of the instance. It is the same for
+	// all instances of the project on that host, without revealing the host.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// Hypervisor is the hypervisor host name of the instance. It is only
+	// exposed to administrators.
+	// +optional
+	Hypervisor string `json:"hypervisor,omitempty"`
+
+	// LaunchedAt is the time the instance was launched.
+	// +optional
+	LaunchedAt *metav1.Time `json:"launchedAt,omitempty"`
+
+	// FloatingIPID is the ID of the floating IP allocated for the machine with
+	// AssociateFloatingIP, which is released when the machine is deleted.
+	// +optional
+	FloatingIPID string `json:"floatingIPID,omitempty"`
+
 	FailureReason *errors.MachineStatusError `json:"errorReason,omitempty"`
 
 	// FailureMessage will be set in the event that there is a terminal problem
@@ -125,6 +299,10 @@ type OpenStackMachineStatus struct {
 	// controller's output.
 	// +optional
 	FailureMessage *string `json:"errorMessage,omitempty"`
+
+	// Conditions defines current service state of the OpenStackMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -155,6 +333,16 @@ type OpenStackMachineList struct {
 	Items           []OpenStackMachine `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the OpenStackMachine resource.
+func (r *OpenStackMachine) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackMachine to the predescribed clusterv1.Conditions.
+func (r *OpenStackMachine) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenStackMachine{}, &OpenStackMachineList{})
 }
@@ -46,6 +46,8 @@ var _ webhook.Validator = &OpenStackMachine{}
 func (r *OpenStackMachine) ValidateCreate() error {
 	var allErrs field.ErrorList
 
+	allErrs = append(allErrs, validateOpenStackMachineSpec(r.Spec, field.NewPath("spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -77,6 +79,26 @@ func (r *OpenStackMachine) ValidateUpdate(old runtime.Object) error {
 	delete(oldOpenStackMachineSpec, "instanceID")
 	delete(newOpenStackMachineSpec, "instanceID")
 
+	// allow changes to the security groups, they are updated on the ports
+	delete(oldOpenStackMachineSpec, "securityGroups")
+	delete(newOpenStackMachineSpec, "securityGroups")
+
+	// allow changes to the image, if the server is rebuilt with it
+	if r.Spec.RebuildOnImageChange {
+		for _, key := range []string{"image", "imageUUID", "imageFilter"} {
+			delete(oldOpenStackMachineSpec, key)
+			delete(newOpenStackMachineSpec, key)
+		}
+	}
+
+	// allow changes to the flavor, if the server is resized to it
+	if r.Spec.ResizeOnFlavorChange {
+		for _, key := range []string{"flavor", "flavorID"} {
+			delete(oldOpenStackMachineSpec, key)
+			delete(newOpenStackMachineSpec, key)
+		}
+	}
+
 	if !reflect.DeepEqual(oldOpenStackMachineSpec, newOpenStackMachineSpec) {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
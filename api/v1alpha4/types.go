@@ -16,6 +16,11 @@ limitations under the License.
 
 package v1alpha4
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // OpenStackMachineTemplateResource describes the data needed to create a OpenStackMachine from a template.
 type OpenStackMachineTemplateResource struct {
 	// Spec is the specification of the desired behavior of the machine.
@@ -57,12 +62,200 @@ type SecurityGroupFilter struct {
 type NetworkParam struct {
 	// The UUID of the network. Required if you omit the port attribute.
 	UUID string `json:"uuid,omitempty"`
-	// A fixed IPv4 address for the NIC.
+	// A fixed IP address for the port of the machine in the network. If
+	// Subnets are given, the port with the subnet which contains the address
+	// gets it. The machine fails if the address is already in use.
 	FixedIP string `json:"fixedIp,omitempty"`
 	// Filters for optional network query
 	Filter Filter `json:"filter,omitempty"`
 	// Subnet within a network to use
 	Subnets []SubnetParam `json:"subnets,omitempty"`
+	// VNICType is the type of the vNIC of the port, e.g. direct for SR-IOV
+	// or macvtap. If unset, normal is used.
+	// +optional
+	VNICType string `json:"vnicType,omitempty"`
+	// Profile is the binding profile of the port, e.g. the capabilities of
+	// hardware offloaded ports. Setting it requires admin privileges by
+	// default.
+	// +optional
+	Profile map[string]string `json:"profile,omitempty"`
+	// DisablePortSecurity disables the port security of the port, so that it
+	// accepts traffic for any address. The security groups of the machine are
+	// not applied to the port then.
+	// +optional
+	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
+	// AllowedAddressPairs are additional addresses the port accepts traffic
+	// for, e.g. a virtual IP moved between the machines by kube-vip.
+	// +optional
+	AllowedAddressPairs []AddressPair `json:"allowedAddressPairs,omitempty"`
+}
+
+// AddressPair is an address which a port accepts traffic for in addition to
+// its fixed IPs.
+type AddressPair struct {
+	// IPAddress is an IP address or a CIDR.
+	IPAddress string `json:"ipAddress"`
+	// MACAddress is the MAC address of the pair. If unset, the MAC address of
+	// the port is used.
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+}
+
+// PortOpts defines a port of a machine.
+type PortOpts struct {
+	// NetworkID is the ID of the network the port is created in. If unset,
+	// the port is created in the network of the cluster.
+	// +optional
+	NetworkID string `json:"networkId,omitempty"`
+	// PortID is the ID of an existing port the machine is attached to
+	// instead of a created port, e.g. a port with a reserved address. The
+	// port is not deleted with the machine. All other options of the port
+	// but Primary are ignored then.
+	// +optional
+	PortID string `json:"portId,omitempty"`
+	// Primary marks the port as the primary interface of the node. Its first
+	// IPv4 and IPv6 addresses become the access IPs of the instance, which
+	// are the internal addresses of the node, e.g. the node-ip of the
+	// kubelet. At most one port of a machine can be primary.
+	// +optional
+	Primary bool `json:"primary,omitempty"`
+	// NameSuffix is appended to the name of the machine to name the port. If
+	// unset, the index of the port in the list is used.
+	// +optional
+	NameSuffix string `json:"nameSuffix,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// AdminStateUp sets the administrative state of the port. If unset, the
+	// port is up.
+	// +optional
+	AdminStateUp *bool `json:"adminStateUp,omitempty"`
+	// MACAddress is the MAC address of the port. If unset, Neutron generates
+	// one.
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+	// FixedIPs are the fixed IP addresses of the port. If unset, the port
+	// gets an address of the subnet of the cluster when it is created in the
+	// network of the cluster, or of any subnet of its network otherwise.
+	// +optional
+	FixedIPs []FixedIP `json:"fixedIPs,omitempty"`
+	// SecurityGroups replaces the security groups of the machine for the
+	// port, including the managed security group. If unset, the port gets
+	// the security groups of the machine.
+	// +optional
+	SecurityGroups *[]SecurityGroupParam `json:"securityGroups,omitempty"`
+	// AllowedAddressPairs are additional addresses the port accepts traffic
+	// for.
+	// +optional
+	AllowedAddressPairs []AddressPair `json:"allowedAddressPairs,omitempty"`
+	// DisablePortSecurity disables the port security of the port.
+	// +optional
+	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
+	// QoSPolicy is the Neutron QoS policy of the port, e.g. to limit the
+	// bandwidth of the machine on a shared network. If unset, the port gets
+	// the QoS policy of its network, if any.
+	// +optional
+	QoSPolicy *QoSPolicyParam `json:"qosPolicy,omitempty"`
+	// Trunk creates a trunk with the port as parent port. If unset, the Trunk
+	// field of the machine is used.
+	// +optional
+	Trunk *bool `json:"trunk,omitempty"`
+	// HostID is the ID of the host the port is bound to. Setting it requires
+	// admin privileges by default.
+	// +optional
+	HostID string `json:"hostId,omitempty"`
+	// VNICType is the type of the vNIC of the port.
+	// +optional
+	VNICType string `json:"vnicType,omitempty"`
+	// Profile is the binding profile of the port.
+	// +optional
+	Profile map[string]string `json:"profile,omitempty"`
+	// DNSName is the dns_name of the port, which the DNS integration of
+	// Neutron uses for the internal DNS records of the port. If unset, the
+	// primary port gets the first label of the Hostname of the machine, if any.
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+	// DNSDomain is the dns_domain of the port, the zone its DNS records are
+	// published in by an external DNS service like Designate. It requires the
+	// dns_domain_ports extension of Neutron. If unset, the dns_domain of the
+	// network is used.
+	// +optional
+	DNSDomain string `json:"dnsDomain,omitempty"`
+	// Tags are set on the port.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// QoSPolicyParam selects a Neutron QoS policy by its UUID, its name or a filter.
+// The selection must match exactly one policy.
+type QoSPolicyParam struct {
+	// QoS policy UID
+	UUID string `json:"uuid,omitempty"`
+	// QoS policy name
+	Name string `json:"name,omitempty"`
+	// Filters used to query QoS policies in openstack
+	Filter QoSPolicyFilter `json:"filter,omitempty"`
+}
+
+type QoSPolicyFilter struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	TenantID    string `json:"tenantId,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+	Tags        string `json:"tags,omitempty"`
+	TagsAny     string `json:"tagsAny,omitempty"`
+	NotTags     string `json:"notTags,omitempty"`
+	NotTagsAny  string `json:"notTagsAny,omitempty"`
+}
+
+// FixedIP is a fixed IP address of a port.
+type FixedIP struct {
+	// SubnetID is the ID of the subnet the address is taken from.
+	SubnetID string `json:"subnetId"`
+	// IPAddress is the address. If unset, any free address of the subnet is
+	// used.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+}
+
+// Subport is a subport of the trunk of a machine.
+type Subport struct {
+	// NetworkID is the ID of the network the port of the subport is created
+	// in.
+	NetworkID string `json:"networkId"`
+	// SubnetID is the ID of the subnet the port of the subport gets its
+	// address from. If unset, any subnet of the network is used.
+	// +optional
+	SubnetID string `json:"subnetId,omitempty"`
+	// SegmentationID is the VLAN ID of the subport.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	SegmentationID int `json:"segmentationId"`
+}
+
+// MachineRole is the role of a machine in the cluster.
+// +kubebuilder:validation:Enum=control-plane;worker
+type MachineRole string
+
+var (
+	// MachineRoleControlPlane is the role of control plane machines.
+	MachineRoleControlPlane = MachineRole("control-plane")
+
+	// MachineRoleWorker is the role of all other machines.
+	MachineRoleWorker = MachineRole("worker")
+)
+
+// AdditionalNetwork is a network which is attached to the machines of a cluster
+// in addition to the networks of the machines.
+type AdditionalNetwork struct {
+	NetworkParam `json:",inline"`
+	// Roles of the machines the network is attached to. If empty, the network is
+	// attached to all machines.
+	// +optional
+	Roles []MachineRole `json:"roles,omitempty"`
+	// ExcludeRoles are the roles of the machines the network is not attached to.
+	// +optional
+	ExcludeRoles []MachineRole `json:"excludeRoles,omitempty"`
 }
 
 type Filter struct {
@@ -116,6 +309,126 @@ type SubnetFilter struct {
 	NotTagsAny      string `json:"notTagsAny,omitempty"`
 }
 
+// ProviderNetwork configures the provider attributes of a Neutron network.
+type ProviderNetwork struct {
+	// NetworkType is the type of the physical network, e.g. vlan or flat.
+	// +kubebuilder:validation:Enum=flat;vlan;vxlan;gre;geneve
+	NetworkType string `json:"networkType"`
+	// PhysicalNetwork is the name of the physical network, as configured in
+	// Neutron. Required for flat and vlan networks.
+	// +optional
+	PhysicalNetwork string `json:"physicalNetwork,omitempty"`
+	// SegmentationID is the ID of the segment, e.g. the VLAN ID. If unset, a free
+	// ID is allocated by Neutron.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SegmentationID int `json:"segmentationId,omitempty"`
+}
+
+// RouterFilter selects an existing Neutron router.
+type RouterFilter struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	TenantID    string `json:"tenantId,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+	Tags        string `json:"tags,omitempty"`
+	TagsAny     string `json:"tagsAny,omitempty"`
+	NotTags     string `json:"notTags,omitempty"`
+	NotTagsAny  string `json:"notTagsAny,omitempty"`
+}
+
+// SubnetPool selects a Neutron subnet pool by ID, or by name and address scope.
+type SubnetPool struct {
+	// ID of the subnet pool.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Name of the subnet pool, used if ID is not set.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// AddressScopeID restricts the subnet pool to those of an address scope, used
+	// if ID is not set. The subnets of an address scope are routed without NAT.
+	// +optional
+	AddressScopeID string `json:"addressScopeId,omitempty"`
+	// PrefixLength of the CIDR allocated from the subnet pool. Defaults to the
+	// default prefix length of the subnet pool.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	PrefixLength int `json:"prefixLength,omitempty"`
+}
+
+// NodeDNSRecords configures the Designate records of the machines of a cluster.
+type NodeDNSRecords struct {
+	// Zone is the name of the Designate zone in which an A or AAAA record named
+	// after the machine is created for its fixed and floating IPs, e.g.
+	// "nodes.example.com.".
+	// +kubebuilder:validation:Pattern=`^.*\.$`
+	Zone string `json:"zone"`
+	// ReverseZones are the names of Designate reverse zones, e.g. "0.6.10.in-addr.arpa.".
+	// A PTR record is created for every address of a machine which is contained
+	// in one of the zones.
+	// +optional
+	ReverseZones []string `json:"reverseZones,omitempty"`
+	// TTL of the records in seconds. Defaults to the TTL of the zone.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL int `json:"ttl,omitempty"`
+}
+
+// SecurityGroupRuleSpec defines an additional rule of the managed security groups.
+// At most one of RemoteIPPrefix and RemoteManagedGroups can be set. Without
+// either, the rule applies to traffic from or to any address.
+type SecurityGroupRuleSpec struct {
+	// Description of the rule. It identifies the rule together with its other
+	// fields.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Direction of the traffic the rule applies to.
+	// +kubebuilder:validation:Enum=ingress;egress
+	Direction string `json:"direction"`
+	// EtherType is the IP version of the traffic. If unset, it is taken from
+	// RemoteIPPrefix, or IPv4.
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	// +optional
+	EtherType string `json:"etherType,omitempty"`
+	// Protocol of the traffic, e.g. tcp, udp, icmp or a protocol number. If
+	// unset, the rule applies to all protocols.
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// PortRangeMin is the first port of the rule. Requires Protocol tcp or udp.
+	// +optional
+	PortRangeMin int `json:"portRangeMin,omitempty"`
+	// PortRangeMax is the last port of the rule. If unset, only PortRangeMin is
+	// allowed.
+	// +optional
+	PortRangeMax int `json:"portRangeMax,omitempty"`
+	// RemoteIPPrefix is the CIDR or address the traffic comes from or goes to.
+	// +optional
+	RemoteIPPrefix string `json:"remoteIPPrefix,omitempty"`
+	// RemoteManagedGroups are the roles of the machines whose managed security
+	// groups the traffic comes from or goes to. A rule is added for each of
+	// them.
+	// +optional
+	RemoteManagedGroups []MachineRole `json:"remoteManagedGroups,omitempty"`
+}
+
+// EgressLockdown lists the destinations outside of the cluster which the machines
+// may connect to if egress traffic is restricted.
+type EgressLockdown struct {
+	// DNSCIDRs are the CIDRs of the DNS servers, which are allowed on port 53 via
+	// TCP and UDP. Defaults to the DNSNameservers of the cluster.
+	// +optional
+	DNSCIDRs []string `json:"dnsCidrs,omitempty"`
+	// NTPCIDRs are the CIDRs of the NTP servers, which are allowed on port 123 via UDP.
+	// +optional
+	NTPCIDRs []string `json:"ntpCidrs,omitempty"`
+	// RegistryCIDRs are the CIDRs of the container registries, which are allowed on
+	// port 443 via TCP.
+	// +optional
+	RegistryCIDRs []string `json:"registryCidrs,omitempty"`
+}
+
 type Instance struct {
 	ID             string            `json:"id,omitempty"`
 	Name           string            `json:"name,omitempty"`
@@ -126,7 +439,10 @@ type Instance struct {
 	Subnet         string            `json:"subnet,omitempty"`
 	Tags           []string          `json:"tags,omitempty"`
 	Image          string            `json:"image,omitempty"`
+	ImageUUID      string            `json:"imageUUID,omitempty"`
+	ImageFilter    *ImageFilter      `json:"imageFilter,omitempty"`
 	Flavor         string            `json:"flavor,omitempty"`
+	FlavorID       string            `json:"flavorID,omitempty"`
 	SSHKeyName     string            `json:"sshKeyName,omitempty"`
 	UserData       string            `json:"userData,omitempty"`
 	Metadata       map[string]string `json:"metadata,omitempty"`
@@ -135,14 +451,140 @@ type Instance struct {
 	ServerGroupID  string            `json:"serverGroupID,omitempty"`
 	State          InstanceState     `json:"state,omitempty"`
 	IP             string            `json:"ip,omitempty"`
+	IPv6           string            `json:"ipv6,omitempty"`
 	FloatingIP     string            `json:"floatingIP,omitempty"`
+	// Addresses are all addresses of the instance.
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+	// AvailabilityZone is the availability zone the instance runs in.
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// HostID identifies the compute host of the instance. It is the same for
+	// all instances of the project on that host, without revealing the host.
+	HostID string `json:"hostID,omitempty"`
+	// Hypervisor is the hypervisor host name of the instance. It is only
+	// exposed to administrators.
+	Hypervisor string `json:"hypervisor,omitempty"`
+	// LaunchedAt is the time the instance was launched.
+	LaunchedAt *metav1.Time `json:"launchedAt,omitempty"`
+}
+
+// ShelveOnDelete defines how long the instance of a deleted machine is kept
+// shelved.
+type ShelveOnDelete struct {
+	// Retention is the time after which the shelved instance is deleted. If
+	// unset, the instance is kept until it is deleted manually or the cluster
+	// is deleted.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+}
+
+// BootstrapCheck defines how to detect that the bootstrap of a machine succeeded.
+// Exactly one of MetadataKey and Port must be set.
+type BootstrapCheck struct {
+	// MetadataKey is a server metadata key which the bootstrap data of the machine
+	// sets once the bootstrap succeeded.
+	// +optional
+	MetadataKey string `json:"metadataKey,omitempty"`
+	// Port is a TCP port which accepts connections once the bootstrap
+	// succeeded, e.g. 10250 for the kubelet. The API server port of a control
+	// plane machine behind the managed API server load balancer is checked
+	// through the load balancer member of the machine, other ports on the
+	// address of the machine.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int `json:"port,omitempty"`
+	// Timeout after which a machine whose bootstrap didn't succeed is marked as
+	// failed. If unset, the machine waits forever.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// ConsoleLogLines is the number of lines of the console log of the instance
+	// which are captured when the bootstrap timed out. They are logged by the
+	// controller and the end of them is attached to an event of the machine.
+	// If unset, the console log isn't captured.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConsoleLogLines int `json:"consoleLogLines,omitempty"`
 }
 
 type RootVolume struct {
+	// SourceType is the type of the source of the root volume, e.g. image. If
+	// it is volume, the instance boots from the existing volume SourceUUID
+	// instead of a new one, and the volume is kept when the instance is deleted.
 	SourceType string `json:"sourceType,omitempty"`
 	SourceUUID string `json:"sourceUUID,omitempty"`
 	DeviceType string `json:"deviceType,omitempty"`
 	Size       int    `json:"diskSize,omitempty"`
+	// VolumeType is the Cinder volume type of the root volume. If unset, the
+	// default volume type is used. Requires Nova api 2.67 minimum unless
+	// AvailabilityZone is set.
+	// +optional
+	VolumeType string `json:"volumeType,omitempty"`
+	// AvailabilityZone is the Cinder availability zone of the root volume. If
+	// set, the volume is created with Cinder before the instance, otherwise Nova
+	// creates it in the availability zone of the instance.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// VolumeTypesByAvailabilityZone maps an availability zone to the Cinder
+	// volume type of the root volume of machines created in that zone. This
+	// allows to share a machine template across availability zones which are
+	// backed by different storage backends. Zones which are not contained use
+	// VolumeType.
+	// +optional
+	VolumeTypesByAvailabilityZone map[string]string `json:"volumeTypesByAvailabilityZone,omitempty"`
+	// PreserveOnDelete keeps the root volume when the instance is deleted,
+	// e.g. for the analysis of a failed machine. The volume has to be deleted
+	// manually afterwards.
+	// +optional
+	PreserveOnDelete bool `json:"preserveOnDelete,omitempty"`
+}
+
+// AdditionalBlockDevice is a Cinder volume which is attached to the instance in
+// addition to the root disk.
+type AdditionalBlockDevice struct {
+	// Name is appended to the name of the instance to form the name of the
+	// volume. It must be unique within the machine.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Size is the size of the volume in GiB.
+	// +kubebuilder:validation:Minimum=1
+	Size int `json:"diskSize"`
+	// VolumeType is the Cinder volume type of the volume. If unset, the default
+	// volume type is used.
+	// +optional
+	VolumeType string `json:"volumeType,omitempty"`
+	// AvailabilityZone is the Cinder availability zone of the volume. If unset,
+	// the default availability zone of Cinder is used.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// Metadata is set on the volume. Cinder volumes have no tags, metadata can
+	// be used to label them instead.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// PreserveOnDelete keeps the volume when the instance is deleted.
+	// +optional
+	PreserveOnDelete bool `json:"preserveOnDelete,omitempty"`
+}
+
+// ImageFilter selects an image by its attributes instead of its name only.
+type ImageFilter struct {
+	// Name is the name of the image.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Tags are tags which the image must all have.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// Properties are properties which the image must have, e.g. os_distro and
+	// os_version.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+	// Visibility is the visibility of the image.
+	// +kubebuilder:validation:Enum=public;private;shared;community
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+	// MostRecent selects the most recently created image if several images
+	// match. Otherwise several matching images are an error.
+	// +optional
+	MostRecent bool `json:"mostRecent,omitempty"`
 }
 
 // Network represents basic information about the associated OpenStach Neutron Network.
@@ -154,7 +596,31 @@ type Network struct {
 	Tags []string `json:"tags,omitempty"`
 
 	Subnet *Subnet `json:"subnet,omitempty"`
-	Router *Router `json:"router,omitempty"`
+	// IPv6Subnet is the IPv6 subnet of a dual-stack network of the cluster.
+	//+optional
+	IPv6Subnet *Subnet `json:"ipv6Subnet,omitempty"`
+	Router     *Router `json:"router,omitempty"`
+
+	// FixedIP is the fixed IP address requested for the port of an instance
+	// in the network.
+	//+optional
+	FixedIP string `json:"fixedIP,omitempty"`
+
+	// VNICType, Profile, DisablePortSecurity and AllowedAddressPairs configure
+	// the port of an instance in the network.
+	//+optional
+	VNICType string `json:"vnicType,omitempty"`
+	//+optional
+	Profile map[string]string `json:"profile,omitempty"`
+	//+optional
+	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
+	//+optional
+	AllowedAddressPairs []AddressPair `json:"allowedAddressPairs,omitempty"`
+
+	// PortOpts are the options of the port of an instance in the network,
+	// if the port is defined in the Ports of the machine.
+	//+optional
+	PortOpts *PortOpts `json:"port,omitempty"`
 
 	// Be careful when using APIServerLoadBalancer, because this field is optional and therefore not
 	// set in all cases
@@ -180,6 +646,80 @@ type Router struct {
 	Tags []string `json:"tags,omitempty"`
 }
 
+// LoadBalancerHealthMonitor configures how the members of a load balancer pool are checked.
+type LoadBalancerHealthMonitor struct {
+	// Type of the health monitor.
+	// +kubebuilder:validation:Enum=TCP;HTTP;HTTPS
+	// +kubebuilder:default=TCP
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Port of the members which is checked. Defaults to the port of the listener.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int `json:"port,omitempty"`
+	// URLPath requested by HTTP and HTTPS health monitors. Defaults to /.
+	// +optional
+	URLPath string `json:"urlPath,omitempty"`
+	// ExpectedCodes are the HTTP status codes of a healthy member, e.g. 200 or 200-299.
+	// Defaults to 200.
+	// +optional
+	ExpectedCodes string `json:"expectedCodes,omitempty"`
+	// Delay is the interval between two checks of a member in seconds.
+	// Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Delay int `json:"delay,omitempty"`
+	// Timeout is the time in seconds after which a check fails. It must be
+	// smaller than Delay. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+	// MaxRetries is the number of failed checks after which a member is
+	// removed from the pool. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+// LoadBalancerListener is an additional listener of the APIServerLoadBalancer,
+// whose traffic is forwarded to the control plane machines.
+type LoadBalancerListener struct {
+	// Protocol of the listener. HTTPS listeners pass the TLS connections
+	// through to the members.
+	// +kubebuilder:validation:Enum=TCP;UDP;HTTPS
+	// +kubebuilder:default=TCP
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// Port of the listener.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+	// TargetPort is the port of the members the traffic is forwarded to, e.g.
+	// a NodePort. Defaults to Port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TargetPort int `json:"targetPort,omitempty"`
+}
+
+// LoadBalancerMember configures the members of a control plane machine in the
+// pools of the APIServerLoadBalancer.
+type LoadBalancerMember struct {
+	// Weight of the members relative to the other members of the pools, e.g. to
+	// prefer machines in the availability zone of the clients. Members with
+	// weight 0 receive no new connections. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	Weight *int `json:"weight,omitempty"`
+	// Backup marks the members as backup members, which only receive traffic
+	// if all other members are down.
+	// +optional
+	Backup bool `json:"backup,omitempty"`
+}
+
 // LoadBalancer represents basic information about the associated OpenStack LoadBalancer.
 type LoadBalancer struct {
 	Name       string `json:"name"`
@@ -188,6 +728,51 @@ type LoadBalancer struct {
 	InternalIP string `json:"internalIP"`
 }
 
+// APIServerVIP is a port which reserves the address of the APIServer on the
+// network of the cluster when no load balancer is used.
+type APIServerVIP struct {
+	// PortID is the ID of the port which reserves the address.
+	PortID string `json:"portID"`
+	// Address is the fixed IP of the port.
+	Address string `json:"address"`
+	// FloatingIP is the floating IP associated with the port.
+	// +optional
+	FloatingIP string `json:"floatingIP,omitempty"`
+}
+
+// ManagedResources contains the IDs of the OpenStack resources created by the cluster controller.
+type ManagedResources struct {
+	// NetworkID is the ID of the network created for the cluster.
+	// +optional
+	NetworkID string `json:"networkID,omitempty"`
+	// SubnetIDs are the IDs of the subnets created in the network of the cluster.
+	// +optional
+	SubnetIDs []string `json:"subnetIDs,omitempty"`
+	// RouterID is the ID of the router created for the cluster.
+	// +optional
+	RouterID string `json:"routerID,omitempty"`
+	// SecurityGroupIDs are the IDs of the managed security groups.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+	// LoadBalancerIDs are the IDs of the APIServer load balancers.
+	// +optional
+	LoadBalancerIDs []string `json:"loadBalancerIDs,omitempty"`
+	// VIPPortIDs are the IDs of the VIP ports of the APIServer load balancers
+	// or of the APIServer VIP.
+	// +optional
+	VIPPortIDs []string `json:"vipPortIDs,omitempty"`
+	// APIServerFloatingIPID is the ID of the floating IP created for the APIServer load balancer.
+	// +optional
+	APIServerFloatingIPID string `json:"apiServerFloatingIPID,omitempty"`
+	// BastionID is the ID of the bastion instance.
+	// +optional
+	BastionID string `json:"bastionID,omitempty"`
+	// BastionFloatingIPID is the ID of the floating IP which was allocated for
+	// the bastion. The floating IP of the instance of the bastion is not recorded.
+	// +optional
+	BastionFloatingIPID string `json:"bastionFloatingIPID,omitempty"`
+}
+
 // SecurityGroup represents the basic information of the associated
 // OpenStack Neutron Security Group.
 type SecurityGroup struct {
@@ -223,6 +808,43 @@ func (r SecurityGroupRule) Equal(x SecurityGroupRule) bool {
 		r.RemoteIPPrefix == x.RemoteIPPrefix)
 }
 
+// FailureDomainFallbackPolicy describes where machines are created if their failure domain is unavailable.
+type FailureDomainFallbackPolicy string
+
+var (
+	// FailureDomainFallbackNone keeps machines in their failure domain.
+	FailureDomainFallbackNone = FailureDomainFallbackPolicy("None")
+
+	// FailureDomainFallbackAnyAvailable moves machines to another available failure domain.
+	FailureDomainFallbackAnyAvailable = FailureDomainFallbackPolicy("AnyAvailable")
+)
+
+// Timeouts are the timeouts of the operations on the OpenStack resources of a cluster.
+type Timeouts struct {
+	// InstanceCreate is the time to wait for a created instance to become
+	// active. If unset, the CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT
+	// environment variable of the controller is used, or 5 minutes.
+	// +optional
+	InstanceCreate *metav1.Duration `json:"instanceCreate,omitempty"`
+	// InstanceDelete is the time to wait for an instance to be deleted. If
+	// unset, 5 minutes are used.
+	// +optional
+	InstanceDelete *metav1.Duration `json:"instanceDelete,omitempty"`
+	// InstanceStop is the time to wait for an instance to shut off before it
+	// is deleted, if StopBeforeDelete is set on the machine. If unset, 5
+	// minutes are used.
+	// +optional
+	InstanceStop *metav1.Duration `json:"instanceStop,omitempty"`
+	// PortDelete is the time for which the deletion of a port of an instance
+	// is retried. If unset, 3 minutes are used.
+	// +optional
+	PortDelete *metav1.Duration `json:"portDelete,omitempty"`
+	// TrunkDelete is the time for which the deletion of a trunk of an instance
+	// is retried. If unset, 3 minutes are used.
+	// +optional
+	TrunkDelete *metav1.Duration `json:"trunkDelete,omitempty"`
+}
+
 // InstanceState describes the state of an OpenStack instance.
 type InstanceState string
 
@@ -236,6 +858,61 @@ var (
 	InstanceStateStopped = InstanceState("STOPPED")
 
 	InstanceStateShutoff = InstanceState("SHUTOFF")
+
+	InstanceStateRebuild = InstanceState("REBUILD")
+
+	InstanceStateResize = InstanceState("RESIZE")
+
+	InstanceStateVerifyResize = InstanceState("VERIFY_RESIZE")
+
+	InstanceStateShelved = InstanceState("SHELVED")
+
+	InstanceStateShelvedOffloaded = InstanceState("SHELVED_OFFLOADED")
+)
+
+// IPFamily is the IP family of an address.
+// +kubebuilder:validation:Enum=IPv4;IPv6
+type IPFamily string
+
+var (
+	// IPFamilyIPv4 selects IPv4 addresses.
+	IPFamilyIPv4 = IPFamily("IPv4")
+	// IPFamilyIPv6 selects IPv6 addresses.
+	IPFamilyIPv6 = IPFamily("IPv6")
+)
+
+// ManagedSecurityGroupProfile selects the rules for the traffic of a CNI between
+// the nodes in the managed security groups.
+// +kubebuilder:validation:Enum=calico;cilium;flannel-vxlan;none
+type ManagedSecurityGroupProfile string
+
+var (
+	// ManagedSecurityGroupProfileCalico allows BGP, IP-in-IP and VXLAN of
+	// Calico.
+	ManagedSecurityGroupProfileCalico = ManagedSecurityGroupProfile("calico")
+	// ManagedSecurityGroupProfileCilium allows VXLAN, Geneve and the health
+	// checks of Cilium.
+	ManagedSecurityGroupProfileCilium = ManagedSecurityGroupProfile("cilium")
+	// ManagedSecurityGroupProfileFlannelVXLAN allows VXLAN of flannel.
+	ManagedSecurityGroupProfileFlannelVXLAN = ManagedSecurityGroupProfile("flannel-vxlan")
+	// ManagedSecurityGroupProfileNone allows no CNI traffic, e.g. if the rules
+	// are added with AllNodesSecurityGroupRules.
+	ManagedSecurityGroupProfileNone = ManagedSecurityGroupProfile("none")
+)
+
+// ServerGroupPolicy is the policy of a managed server group.
+// +kubebuilder:validation:Enum=affinity;anti-affinity;soft-anti-affinity
+type ServerGroupPolicy string
+
+var (
+	// ServerGroupPolicyAffinity places the machines on the same hypervisor.
+	ServerGroupPolicyAffinity = ServerGroupPolicy("affinity")
+	// ServerGroupPolicyAntiAffinity places the machines on different
+	// hypervisors. A machine fails if there is no hypervisor left.
+	ServerGroupPolicyAntiAffinity = ServerGroupPolicy("anti-affinity")
+	// ServerGroupPolicySoftAntiAffinity places the machines on different
+	// hypervisors as far as possible.
+	ServerGroupPolicySoftAntiAffinity = ServerGroupPolicy("soft-anti-affinity")
 )
 
 // Bastion represents basic information about the bastion node.
@@ -243,7 +920,10 @@ type Bastion struct {
 	//+optional
 	Enabled bool `json:"enabled"`
 
-	// Instance for the bastion itself
+	// Instance for the bastion itself. The options of the instance of a machine
+	// are supported, e.g. the image, flavor, root volume, security groups,
+	// networks, ports, tags and server metadata, except for those which depend
+	// on a Machine like user data and trunks.
 	Instance OpenStackMachineSpec `json:"instance,omitempty"`
 
 	//+optional
@@ -33,3 +33,39 @@ func aggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorLis
 		allErrs,
 	)
 }
+
+// validateOpenStackMachineSpec validates the fields of a machine spec which
+// are shared by OpenStackMachines and OpenStackMachineTemplates.
+func validateOpenStackMachineSpec(spec OpenStackMachineSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Flavor == "" && spec.FlavorID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("flavor"), "either flavor or flavorID must be set"))
+	}
+
+	if spec.Flavor != "" && spec.FlavorID != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("flavorID"), "cannot be set together with flavor"))
+	}
+
+	if spec.Image != "" && spec.ImageUUID != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("imageUUID"), "cannot be set together with image"))
+	}
+
+	if spec.ImageFilter != nil && (spec.Image != "" || spec.ImageUUID != "") {
+		allErrs = append(allErrs, field.Forbidden(path.Child("imageFilter"), "cannot be set together with image or imageUUID"))
+	}
+
+	if spec.ServerGroupID != "" && spec.ServerGroupName != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("serverGroupName"), "cannot be set together with serverGroupID"))
+	}
+
+	if spec.RebuildOnImageChange && spec.RootVolume != nil && spec.RootVolume.Size != 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("rebuildOnImageChange"), "cannot be set for machines which boot from a volume"))
+	}
+
+	if check := spec.BootstrapCheck; check != nil && (check.MetadataKey == "") == (check.Port == 0) {
+		allErrs = append(allErrs, field.Invalid(path.Child("bootstrapCheck"), check, "exactly one of metadataKey and port must be set"))
+	}
+
+	return allErrs
+}
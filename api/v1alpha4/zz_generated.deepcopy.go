@@ -22,11 +22,90 @@ package v1alpha4
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerVIP) DeepCopyInto(out *APIServerVIP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerVIP.
+func (in *APIServerVIP) DeepCopy() *APIServerVIP {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerVIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalBlockDevice) DeepCopyInto(out *AdditionalBlockDevice) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalBlockDevice.
+func (in *AdditionalBlockDevice) DeepCopy() *AdditionalBlockDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalBlockDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalNetwork) DeepCopyInto(out *AdditionalNetwork) {
+	*out = *in
+	in.NetworkParam.DeepCopyInto(&out.NetworkParam)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]MachineRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeRoles != nil {
+		in, out := &in.ExcludeRoles, &out.ExcludeRoles
+		*out = make([]MachineRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalNetwork.
+func (in *AdditionalNetwork) DeepCopy() *AdditionalNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressPair) DeepCopyInto(out *AddressPair) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressPair.
+func (in *AddressPair) DeepCopy() *AddressPair {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bastion) DeepCopyInto(out *Bastion) {
 	*out = *in
@@ -43,6 +122,56 @@ func (in *Bastion) DeepCopy() *Bastion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapCheck) DeepCopyInto(out *BootstrapCheck) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapCheck.
+func (in *BootstrapCheck) DeepCopy() *BootstrapCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressLockdown) DeepCopyInto(out *EgressLockdown) {
+	*out = *in
+	if in.DNSCIDRs != nil {
+		in, out := &in.DNSCIDRs, &out.DNSCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NTPCIDRs != nil {
+		in, out := &in.NTPCIDRs, &out.NTPCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryCIDRs != nil {
+		in, out := &in.RegistryCIDRs, &out.RegistryCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressLockdown.
+func (in *EgressLockdown) DeepCopy() *EgressLockdown {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressLockdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalRouterIPParam) DeepCopyInto(out *ExternalRouterIPParam) {
 	*out = *in
@@ -84,6 +213,48 @@ func (in *Filter) DeepCopy() *Filter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixedIP) DeepCopyInto(out *FixedIP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixedIP.
+func (in *FixedIP) DeepCopy() *FixedIP {
+	if in == nil {
+		return nil
+	}
+	out := new(FixedIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageFilter) DeepCopyInto(out *ImageFilter) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFilter.
+func (in *ImageFilter) DeepCopy() *ImageFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Instance) DeepCopyInto(out *Instance) {
 	*out = *in
@@ -112,6 +283,11 @@ func (in *Instance) DeepCopyInto(out *Instance) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageFilter != nil {
+		in, out := &in.ImageFilter, &out.ImageFilter
+		*out = new(ImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = make(map[string]string, len(*in))
@@ -127,7 +303,16 @@ func (in *Instance) DeepCopyInto(out *Instance) {
 	if in.RootVolume != nil {
 		in, out := &in.RootVolume, &out.RootVolume
 		*out = new(RootVolume)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]v1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.LaunchedAt != nil {
+		in, out := &in.LaunchedAt, &out.LaunchedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
@@ -156,6 +341,91 @@ func (in *LoadBalancer) DeepCopy() *LoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthMonitor) DeepCopyInto(out *LoadBalancerHealthMonitor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthMonitor.
+func (in *LoadBalancerHealthMonitor) DeepCopy() *LoadBalancerHealthMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerListener) DeepCopyInto(out *LoadBalancerListener) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerListener.
+func (in *LoadBalancerListener) DeepCopy() *LoadBalancerListener {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerMember) DeepCopyInto(out *LoadBalancerMember) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerMember.
+func (in *LoadBalancerMember) DeepCopy() *LoadBalancerMember {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedResources) DeepCopyInto(out *ManagedResources) {
+	*out = *in
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerIDs != nil {
+		in, out := &in.LoadBalancerIDs, &out.LoadBalancerIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VIPPortIDs != nil {
+		in, out := &in.VIPPortIDs, &out.VIPPortIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedResources.
+func (in *ManagedResources) DeepCopy() *ManagedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
@@ -169,6 +439,11 @@ func (in *Network) DeepCopyInto(out *Network) {
 		*out = new(Subnet)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPv6Subnet != nil {
+		in, out := &in.IPv6Subnet, &out.IPv6Subnet
+		*out = new(Subnet)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Router != nil {
 		in, out := &in.Router, &out.Router
 		*out = new(Router)
@@ -179,6 +454,23 @@ func (in *Network) DeepCopyInto(out *Network) {
 		*out = new(LoadBalancer)
 		**out = **in
 	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedAddressPairs != nil {
+		in, out := &in.AllowedAddressPairs, &out.AllowedAddressPairs
+		*out = make([]AddressPair, len(*in))
+		copy(*out, *in)
+	}
+	if in.PortOpts != nil {
+		in, out := &in.PortOpts, &out.PortOpts
+		*out = new(PortOpts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
@@ -202,6 +494,18 @@ func (in *NetworkParam) DeepCopyInto(out *NetworkParam) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedAddressPairs != nil {
+		in, out := &in.AllowedAddressPairs, &out.AllowedAddressPairs
+		*out = make([]AddressPair, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkParam.
@@ -214,6 +518,26 @@ func (in *NetworkParam) DeepCopy() *NetworkParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDNSRecords) DeepCopyInto(out *NodeDNSRecords) {
+	*out = *in
+	if in.ReverseZones != nil {
+		in, out := &in.ReverseZones, &out.ReverseZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDNSRecords.
+func (in *NodeDNSRecords) DeepCopy() *NodeDNSRecords {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDNSRecords)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackCluster) DeepCopyInto(out *OpenStackCluster) {
 	*out = *in
@@ -281,13 +605,40 @@ func (in *OpenStackClusterSpec) DeepCopyInto(out *OpenStackClusterSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.NodeSubnetPool != nil {
+		in, out := &in.NodeSubnetPool, &out.NodeSubnetPool
+		*out = new(SubnetPool)
+		**out = **in
+	}
 	in.Network.DeepCopyInto(&out.Network)
 	in.Subnet.DeepCopyInto(&out.Subnet)
+	if in.Router != nil {
+		in, out := &in.Router, &out.Router
+		*out = new(RouterFilter)
+		**out = **in
+	}
+	if in.AdditionalNetworks != nil {
+		in, out := &in.AdditionalNetworks, &out.AdditionalNetworks
+		*out = make([]AdditionalNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.DNSNameservers != nil {
 		in, out := &in.DNSNameservers, &out.DNSNameservers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProviderNetwork != nil {
+		in, out := &in.ProviderNetwork, &out.ProviderNetwork
+		*out = new(ProviderNetwork)
+		**out = **in
+	}
+	if in.NodeDNSRecords != nil {
+		in, out := &in.NodeDNSRecords, &out.NodeDNSRecords
+		*out = new(NodeDNSRecords)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExternalRouterIPs != nil {
 		in, out := &in.ExternalRouterIPs, &out.ExternalRouterIPs
 		*out = make([]ExternalRouterIPParam, len(*in))
@@ -295,22 +646,110 @@ func (in *OpenStackClusterSpec) DeepCopyInto(out *OpenStackClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RouterAdditionalSubnets != nil {
+		in, out := &in.RouterAdditionalSubnets, &out.RouterAdditionalSubnets
+		*out = make([]SubnetParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalNetwork != nil {
+		in, out := &in.ExternalNetwork, &out.ExternalNetwork
+		*out = new(Filter)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.APIServerLoadBalancerAdditionalPorts != nil {
 		in, out := &in.APIServerLoadBalancerAdditionalPorts, &out.APIServerLoadBalancerAdditionalPorts
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.APIServerLoadBalancerHealthMonitor != nil {
+		in, out := &in.APIServerLoadBalancerHealthMonitor, &out.APIServerLoadBalancerHealthMonitor
+		*out = new(LoadBalancerHealthMonitor)
+		**out = **in
+	}
+	if in.APIServerLoadBalancerAdditionalPortsHealthMonitor != nil {
+		in, out := &in.APIServerLoadBalancerAdditionalPortsHealthMonitor, &out.APIServerLoadBalancerAdditionalPortsHealthMonitor
+		*out = new(LoadBalancerHealthMonitor)
+		**out = **in
+	}
+	if in.APIServerLoadBalancerAdditionalListeners != nil {
+		in, out := &in.APIServerLoadBalancerAdditionalListeners, &out.APIServerLoadBalancerAdditionalListeners
+		*out = make([]LoadBalancerListener, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIServerAllowedCIDRs != nil {
+		in, out := &in.APIServerAllowedCIDRs, &out.APIServerAllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIServerLoadBalancerIPv6Subnet != nil {
+		in, out := &in.APIServerLoadBalancerIPv6Subnet, &out.APIServerLoadBalancerIPv6Subnet
+		*out = new(SubnetFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressLockdown != nil {
+		in, out := &in.EgressLockdown, &out.EgressLockdown
+		*out = new(EgressLockdown)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllNodesSecurityGroupRules != nil {
+		in, out := &in.AllNodesSecurityGroupRules, &out.AllNodesSecurityGroupRules
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlaneSecurityGroupRules != nil {
+		in, out := &in.ControlPlaneSecurityGroupRules, &out.ControlPlaneSecurityGroupRules
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkerSecurityGroupRules != nil {
+		in, out := &in.WorkerSecurityGroupRules, &out.WorkerSecurityGroupRules
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ComputeTags != nil {
+		in, out := &in.ComputeTags, &out.ComputeTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkTags != nil {
+		in, out := &in.NetworkTags, &out.NetworkTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigDrive != nil {
+		in, out := &in.ConfigDrive, &out.ConfigDrive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(Timeouts)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	if in.ControlPlaneAvailabilityZones != nil {
 		in, out := &in.ControlPlaneAvailabilityZones, &out.ControlPlaneAvailabilityZones
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ControlPlaneOmitAvailabilityZones != nil {
+		in, out := &in.ControlPlaneOmitAvailabilityZones, &out.ControlPlaneOmitAvailabilityZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Bastion != nil {
 		in, out := &in.Bastion, &out.Bastion
 		*out = new(Bastion)
@@ -368,6 +807,28 @@ func (in *OpenStackClusterStatus) DeepCopyInto(out *OpenStackClusterStatus) {
 		*out = new(Instance)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.APIServerLoadBalancerIPv6 != nil {
+		in, out := &in.APIServerLoadBalancerIPv6, &out.APIServerLoadBalancerIPv6
+		*out = new(LoadBalancer)
+		**out = **in
+	}
+	if in.APIServerVIP != nil {
+		in, out := &in.APIServerVIP, &out.APIServerVIP
+		*out = new(APIServerVIP)
+		**out = **in
+	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = new(ManagedResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterStatus.
@@ -457,6 +918,11 @@ func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.ImageFilter != nil {
+		in, out := &in.ImageFilter, &out.ImageFilter
+		*out = new(ImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
 		*out = make([]NetworkParam, len(*in))
@@ -464,6 +930,18 @@ func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]PortOpts, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LoadBalancerMember != nil {
+		in, out := &in.LoadBalancerMember, &out.LoadBalancerMember
+		*out = new(LoadBalancerMember)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecurityGroups != nil {
 		in, out := &in.SecurityGroups, &out.SecurityGroups
 		*out = make([]SecurityGroupParam, len(*in))
@@ -474,6 +952,11 @@ func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.Subports != nil {
+		in, out := &in.Subports, &out.Subports
+		*out = make([]Subport, len(*in))
+		copy(*out, *in)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
@@ -494,7 +977,24 @@ func (in *OpenStackMachineSpec) DeepCopyInto(out *OpenStackMachineSpec) {
 	if in.RootVolume != nil {
 		in, out := &in.RootVolume, &out.RootVolume
 		*out = new(RootVolume)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalBlockDevices != nil {
+		in, out := &in.AdditionalBlockDevices, &out.AdditionalBlockDevices
+		*out = make([]AdditionalBlockDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootstrapCheck != nil {
+		in, out := &in.BootstrapCheck, &out.BootstrapCheck
+		*out = new(BootstrapCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShelveOnDelete != nil {
+		in, out := &in.ShelveOnDelete, &out.ShelveOnDelete
+		*out = new(ShelveOnDelete)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -521,6 +1021,10 @@ func (in *OpenStackMachineStatus) DeepCopyInto(out *OpenStackMachineStatus) {
 		*out = new(InstanceState)
 		**out = **in
 	}
+	if in.LaunchedAt != nil {
+		in, out := &in.LaunchedAt, &out.LaunchedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -531,6 +1035,13 @@ func (in *OpenStackMachineStatus) DeepCopyInto(out *OpenStackMachineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackMachineStatus.
@@ -633,9 +1144,123 @@ func (in *OpenStackMachineTemplateSpec) DeepCopy() *OpenStackMachineTemplateSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortOpts) DeepCopyInto(out *PortOpts) {
+	*out = *in
+	if in.AdminStateUp != nil {
+		in, out := &in.AdminStateUp, &out.AdminStateUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FixedIPs != nil {
+		in, out := &in.FixedIPs, &out.FixedIPs
+		*out = make([]FixedIP, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = new([]SecurityGroupParam)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]SecurityGroupParam, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.AllowedAddressPairs != nil {
+		in, out := &in.AllowedAddressPairs, &out.AllowedAddressPairs
+		*out = make([]AddressPair, len(*in))
+		copy(*out, *in)
+	}
+	if in.QoSPolicy != nil {
+		in, out := &in.QoSPolicy, &out.QoSPolicy
+		*out = new(QoSPolicyParam)
+		**out = **in
+	}
+	if in.Trunk != nil {
+		in, out := &in.Trunk, &out.Trunk
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortOpts.
+func (in *PortOpts) DeepCopy() *PortOpts {
+	if in == nil {
+		return nil
+	}
+	out := new(PortOpts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderNetwork) DeepCopyInto(out *ProviderNetwork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderNetwork.
+func (in *ProviderNetwork) DeepCopy() *ProviderNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSPolicyFilter) DeepCopyInto(out *QoSPolicyFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSPolicyFilter.
+func (in *QoSPolicyFilter) DeepCopy() *QoSPolicyFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSPolicyFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSPolicyParam) DeepCopyInto(out *QoSPolicyParam) {
+	*out = *in
+	out.Filter = in.Filter
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSPolicyParam.
+func (in *QoSPolicyParam) DeepCopy() *QoSPolicyParam {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSPolicyParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RootVolume) DeepCopyInto(out *RootVolume) {
 	*out = *in
+	if in.VolumeTypesByAvailabilityZone != nil {
+		in, out := &in.VolumeTypesByAvailabilityZone, &out.VolumeTypesByAvailabilityZone
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootVolume.
@@ -668,6 +1293,21 @@ func (in *Router) DeepCopy() *Router {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterFilter) DeepCopyInto(out *RouterFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterFilter.
+func (in *RouterFilter) DeepCopy() *RouterFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
@@ -734,6 +1374,46 @@ func (in *SecurityGroupRule) DeepCopy() *SecurityGroupRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupRuleSpec) DeepCopyInto(out *SecurityGroupRuleSpec) {
+	*out = *in
+	if in.RemoteManagedGroups != nil {
+		in, out := &in.RemoteManagedGroups, &out.RemoteManagedGroups
+		*out = make([]MachineRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRuleSpec.
+func (in *SecurityGroupRuleSpec) DeepCopy() *SecurityGroupRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShelveOnDelete) DeepCopyInto(out *ShelveOnDelete) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShelveOnDelete.
+func (in *ShelveOnDelete) DeepCopy() *ShelveOnDelete {
+	if in == nil {
+		return nil
+	}
+	out := new(ShelveOnDelete)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subnet) DeepCopyInto(out *Subnet) {
 	*out = *in
@@ -789,3 +1469,73 @@ func (in *SubnetParam) DeepCopy() *SubnetParam {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetPool) DeepCopyInto(out *SubnetPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetPool.
+func (in *SubnetPool) DeepCopy() *SubnetPool {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subport) DeepCopyInto(out *Subport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subport.
+func (in *Subport) DeepCopy() *Subport {
+	if in == nil {
+		return nil
+	}
+	out := new(Subport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Timeouts) DeepCopyInto(out *Timeouts) {
+	*out = *in
+	if in.InstanceCreate != nil {
+		in, out := &in.InstanceCreate, &out.InstanceCreate
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InstanceDelete != nil {
+		in, out := &in.InstanceDelete, &out.InstanceDelete
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InstanceStop != nil {
+		in, out := &in.InstanceStop, &out.InstanceStop
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PortDelete != nil {
+		in, out := &in.PortDelete, &out.PortDelete
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TrunkDelete != nil {
+		in, out := &in.TrunkDelete, &out.TrunkDelete
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeouts.
+func (in *Timeouts) DeepCopy() *Timeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(Timeouts)
+	in.DeepCopyInto(out)
+	return out
+}
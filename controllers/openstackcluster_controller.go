@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/gophercloud/utils/openstack/clientconfig"
@@ -32,6 +35,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -46,10 +50,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/capabilities"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/servergroups"
+)
+
+const (
+	bastionCheckInterval = 5 * time.Minute
 )
 
 // OpenStackClusterReconciler reconciles a OpenStackCluster object.
@@ -57,6 +67,9 @@ type OpenStackClusterReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// LoadBalancerMetricsInterval is the interval at which the statistics of the
+	// API server load balancer are exported as metrics. Zero disables the metrics.
+	LoadBalancerMetricsInterval time.Duration
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,verbs=get;list;watch;create;update;patch;delete
@@ -110,14 +123,14 @@ func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// Handle deleted clusters
 	if !openStackCluster.DeletionTimestamp.IsZero() {
-		return reconcileDelete(ctx, log, r.Client, patchHelper, openStackCluster)
+		return reconcileDelete(ctx, log, r.Client, patchHelper, cluster, openStackCluster)
 	}
 
 	// Handle non-deleted clusters
-	return reconcileNormal(ctx, log, r.Client, patchHelper, cluster, openStackCluster)
+	return reconcileNormal(ctx, log, r.Client, patchHelper, cluster, openStackCluster, r.LoadBalancerMetricsInterval)
 }
 
-func reconcileDelete(ctx context.Context, log logr.Logger, client client.Client, patchHelper *patch.Helper, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+func reconcileDelete(ctx context.Context, log logr.Logger, client client.Client, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
 	log.Info("Reconciling Cluster delete")
 
 	osProviderClient, clientOpts, err := provider.NewClientFromCluster(client, openStackCluster)
@@ -129,6 +142,18 @@ func reconcileDelete(ctx context.Context, log logr.Logger, client client.Client,
 		return reconcile.Result{}, err
 	}
 
+	computeService, err := compute.NewService(osProviderClient, clientOpts, log)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	// The shelved servers of deleted machines keep their ports on the network
+	// of the cluster.
+	if err = computeService.DeleteShelvedInstances(openStackCluster, clusterName, true); err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to delete shelved instances: %v", err)
+	}
+
 	networkingService, err := networking.NewService(osProviderClient, clientOpts, log)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -139,18 +164,30 @@ func reconcileDelete(ctx context.Context, log logr.Logger, client client.Client,
 		return reconcile.Result{}, err
 	}
 
-	if openStackCluster.Spec.ManagedAPIServerLoadBalancer && openStackCluster.Status.Network != nil {
-		if apiLb := openStackCluster.Status.Network.APIServerLoadBalancer; apiLb != nil {
-			if err = loadBalancerService.DeleteLoadBalancer(openStackCluster, apiLb.Name); err != nil {
-				return reconcile.Result{}, errors.Errorf("failed to delete load balancer: %v", err)
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer {
+		if openStackCluster.Spec.APIServerLoadBalancerID != "" {
+			if err = loadBalancerService.DeleteListeners(openStackCluster, clusterName); err != nil {
+				return reconcile.Result{}, errors.Errorf("failed to delete load balancer listeners: %v", err)
 			}
+		}
+		if err = loadBalancerService.DeleteLoadBalancers(openStackCluster, clusterName); err != nil {
+			return reconcile.Result{}, errors.Errorf("failed to delete load balancers: %v", err)
+		}
 
-			if openStackCluster.Spec.APIServerFloatingIP == "" {
+		if openStackCluster.Status.Network != nil {
+			if apiLb := openStackCluster.Status.Network.APIServerLoadBalancer; apiLb != nil && openStackCluster.Spec.APIServerFloatingIP == "" && !openStackCluster.Spec.DisableAPIServerFloatingIP {
 				if err = networkingService.DeleteFloatingIP(openStackCluster, apiLb.IP); err != nil {
 					return reconcile.Result{}, errors.Errorf("failed to delete floating IP: %v", err)
 				}
 			}
 		}
+		loadbalancer.DeleteLoadBalancerMetrics(openStackCluster)
+	}
+
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		if err = networkingService.DeleteAPIServerVIP(openStackCluster); err != nil {
+			return reconcile.Result{}, errors.Errorf("failed to delete API server VIP: %v", err)
+		}
 	}
 
 	if workerSecGroup := openStackCluster.Status.WorkerSecurityGroup; workerSecGroup != nil {
@@ -165,8 +202,26 @@ func reconcileDelete(ctx context.Context, log logr.Logger, client client.Client,
 		}
 	}
 
-	// if NodeCIDR was not set, no network was created.
-	if openStackCluster.Status.Network != nil && openStackCluster.Spec.NodeCIDR != "" {
+	if keyName := openStackCluster.Status.SSHKeyName; keyName != "" {
+		if err = computeService.DeleteKeyPair(openStackCluster, keyName); err != nil {
+			return reconcile.Result{}, errors.Errorf("failed to delete keypair: %v", err)
+		}
+		openStackCluster.Status.SSHKeyName = ""
+	}
+
+	// Sweep the managed server groups which weren't deleted together with
+	// their last machine. Machines with a server group policy get managed
+	// server groups without ManagedServerGroups, so the sweep always runs.
+	serverGroupService, err := servergroups.NewService(osProviderClient, clientOpts, log)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = serverGroupService.DeleteServerGroups(openStackCluster, clusterName); err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to delete server groups: %v", err)
+	}
+
+	// if neither NodeCIDR nor NodeSubnetPool was set, no network was created.
+	if openStackCluster.Status.Network != nil && isNetworkManaged(openStackCluster) {
 		if openStackCluster.Status.Network.Router != nil {
 			if err = networkingService.DeleteRouter(openStackCluster, openStackCluster.Status.Network); err != nil {
 				return ctrl.Result{}, errors.Errorf("failed to delete router: %v", err)
@@ -210,10 +265,16 @@ func deleteBastion(log logr.Logger, osProviderClient *gophercloud.ProviderClient
 		if err = computeService.DeleteBastion(openStackCluster, openStackCluster.Status.Bastion.ID); err != nil {
 			return errors.Errorf("failed to delete bastion: %v", err)
 		}
-		if err = networkingService.DeleteFloatingIP(openStackCluster, openStackCluster.Status.Bastion.FloatingIP); err != nil {
-			return errors.Errorf("failed to delete floating IP: %v", err)
+		// Only a floating IP which was allocated for the bastion is released.
+		if floatingIP := openStackCluster.Status.Bastion.FloatingIP; floatingIP != "" && isBastionFloatingIPManaged(openStackCluster, floatingIP) {
+			if err = networkingService.DeleteFloatingIP(openStackCluster, floatingIP); err != nil {
+				return errors.Errorf("failed to delete floating IP: %v", err)
+			}
 		}
 		openStackCluster.Status.Bastion = nil
+		if openStackCluster.Status.ManagedResources != nil {
+			openStackCluster.Status.ManagedResources.BastionFloatingIPID = ""
+		}
 	}
 
 	if openStackCluster.Status.BastionSecurityGroup != nil {
@@ -226,7 +287,7 @@ func deleteBastion(log logr.Logger, osProviderClient *gophercloud.ProviderClient
 	return nil
 }
 
-func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, loadBalancerMetricsInterval time.Duration) (ctrl.Result, error) {
 	log.Info("Reconciling Cluster")
 
 	// If the OpenStackCluster doesn't have our finalizer, add it.
@@ -236,16 +297,31 @@ func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client,
 		return reconcile.Result{}, err
 	}
 
+	if openStackCluster.Status.ManagedResources == nil {
+		openStackCluster.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+	// Record the created resources even if the reconciliation fails half-way.
+	defer updateManagedResources(openStackCluster)
+
 	osProviderClient, clientOpts, err := provider.NewClientFromCluster(client, openStackCluster)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if err := reconcileCompatibility(log, osProviderClient, clientOpts, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	computeService, err := compute.NewService(osProviderClient, clientOpts, log)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	if err = computeService.ReconcileKeyPair(openStackCluster, clusterName); err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to reconcile keypair: %v", err)
+	}
+
 	err = reconcileNetworkComponents(log, osProviderClient, clientOpts, cluster, openStackCluster)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -255,13 +331,19 @@ func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client,
 		return reconcile.Result{}, err
 	}
 
+	// Delete the shelved servers of deleted machines whose retention has passed.
+	if err = computeService.DeleteShelvedInstances(openStackCluster, clusterName, false); err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to delete shelved instances: %v", err)
+	}
+
 	availabilityZones, err := computeService.GetAvailabilityZones()
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if openStackCluster.Status.FailureDomains == nil {
-		openStackCluster.Status.FailureDomains = make(clusterv1.FailureDomains)
-	}
+	// Publish the availability zones of Nova as failure domains, so that Cluster API
+	// spreads the control plane machines over them. Zones which no longer exist
+	// are dropped.
+	failureDomains := make(clusterv1.FailureDomains)
 	for _, az := range availabilityZones {
 		// I'm actually not sure if that's just my local devstack,
 		// but we probably shouldn't use the "internal" AZ
@@ -269,6 +351,11 @@ func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client,
 			continue
 		}
 
+		// Don't offer unavailable zones for new machines if they may fall back to another one.
+		if openStackCluster.Spec.FailureDomainFallbackPolicy == infrav1.FailureDomainFallbackAnyAvailable && !az.ZoneState.Available {
+			continue
+		}
+
 		found := true
 		// If Az given, then check whether it's in the allow list
 		// If no Az given, then by default put into allow list
@@ -279,15 +366,61 @@ func reconcileNormal(ctx context.Context, log logr.Logger, client client.Client,
 				found = false
 			}
 		}
+		// Omitted Azs are never used for the control plane
+		if contains(openStackCluster.Spec.ControlPlaneOmitAvailabilityZones, az.ZoneName) {
+			found = false
+		}
 
-		openStackCluster.Status.FailureDomains[az.ZoneName] = clusterv1.FailureDomainSpec{
+		failureDomains[az.ZoneName] = clusterv1.FailureDomainSpec{
 			ControlPlane: found,
 		}
 	}
+	openStackCluster.Status.FailureDomains = failureDomains
 
 	openStackCluster.Status.Ready = true
 	log.Info("Reconciled Cluster create successfully")
-	return reconcile.Result{}, nil
+
+	var requeueAfter time.Duration
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer && loadBalancerMetricsInterval > 0 {
+		loadBalancerService, err := loadbalancer.NewService(osProviderClient, clientOpts, log)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		// The metrics are best effort, they must not block the reconciliation.
+		if err := loadBalancerService.CollectLoadBalancerMetrics(openStackCluster); err != nil {
+			log.Info("Failed to collect load balancer metrics", "error", err.Error())
+		}
+		requeueAfter = loadBalancerMetricsInterval
+	}
+	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
+		// Check the bastion regularly, nothing else notices if it is deleted or fails.
+		if requeueAfter == 0 || bastionCheckInterval < requeueAfter {
+			requeueAfter = bastionCheckInterval
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileCompatibility checks that the cloud provides everything the spec
+// requires before any resource is created, so that a missing service or
+// extension is reported at once instead of failing half-way.
+func reconcileCompatibility(log logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, openStackCluster *infrav1.OpenStackCluster) error {
+	capabilitiesService, err := capabilities.NewService(osProviderClient, clientOpts, log)
+	if err != nil {
+		return err
+	}
+
+	missing, err := capabilitiesService.MissingClusterCapabilities(openStackCluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the capabilities of the cloud")
+	}
+	if len(missing) > 0 {
+		message := fmt.Sprintf("the cloud doesn't provide: %s", strings.Join(missing, ", "))
+		conditions.MarkFalse(openStackCluster, infrav1.InfrastructureCompatibleCondition, infrav1.MissingCapabilitiesReason, clusterv1.ConditionSeverityError, message)
+		return errors.New(message)
+	}
+	conditions.MarkTrue(openStackCluster, infrav1.InfrastructureCompatibleCondition)
+	return nil
 }
 
 func reconcileBastion(log logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
@@ -302,27 +435,68 @@ func reconcileBastion(log logr.Logger, osProviderClient *gophercloud.ProviderCli
 		return err
 	}
 
-	instance, err := computeService.InstanceExists(fmt.Sprintf("%s-bastion", cluster.Name))
+	instance, err := computeService.InstanceExists(fmt.Sprintf("%s-bastion", cluster.Name), nil)
 	if err != nil {
 		return err
 	}
-	if instance != nil {
+	if instance != nil && instance.State == infrav1.InstanceStateError {
+		log.Info("Bastion is in ERROR state, recreating it", "id", instance.ID)
+		if err = computeService.DeleteBastion(openStackCluster, instance.ID); err != nil {
+			return errors.Errorf("failed to delete bastion in ERROR state: %v", err)
+		}
+		instance = nil
+	}
+	specFloatingIP := bastionFloatingIP(openStackCluster)
+	if instance != nil && instance.FloatingIP != "" && (specFloatingIP == "" || instance.FloatingIP == specFloatingIP) {
 		openStackCluster.Status.Bastion = instance
 		return nil
 	}
 
-	instance, err = computeService.CreateBastion(openStackCluster, cluster.Name)
-	if err != nil {
-		return errors.Errorf("failed to reconcile bastion: %v", err)
+	if instance == nil {
+		if openStackCluster.Status.Bastion != nil {
+			log.Info("Bastion is missing, recreating it", "id", openStackCluster.Status.Bastion.ID)
+		}
+		instance, err = computeService.CreateBastion(openStackCluster, cluster.Name)
+		if err != nil {
+			return errors.Errorf("failed to reconcile bastion: %v", err)
+		}
+	}
+
+	// Without managed networking, the bastion is reached on its fixed IP.
+	if openStackCluster.Spec.DisableManagedNetworking {
+		openStackCluster.Status.Bastion = instance
+		return nil
 	}
 
 	networkingService, err := networking.NewService(osProviderClient, clientOpts, log)
 	if err != nil {
 		return err
 	}
-	fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster.Spec.Bastion.Instance.FloatingIP)
-	if err != nil {
-		return errors.Errorf("failed to get or create floating IP for bastion: %v", err)
+	var fp *floatingips.FloatingIP
+	managedFloatingIPID := ""
+	if specFloatingIP != "" {
+		fp, err = networkingService.GetBastionFloatingIP(openStackCluster)
+		if err != nil {
+			return errors.Errorf("failed to get floating IP for bastion: %v", err)
+		}
+	} else {
+		floatingIP := ""
+		if openStackCluster.Status.Bastion != nil && isBastionFloatingIPManaged(openStackCluster, openStackCluster.Status.Bastion.FloatingIP) {
+			// Keep the address of a bastion which was deleted or lost its floating IP.
+			floatingIP = openStackCluster.Status.Bastion.FloatingIP
+		}
+		fp, err = networkingService.GetOrCreateFloatingIP(openStackCluster, floatingIP)
+		if err != nil {
+			return errors.Errorf("failed to get or create floating IP for bastion: %v", err)
+		}
+		managedFloatingIPID = fp.ID
+	}
+	// Release the floating IP which was allocated for the bastion before the
+	// floating IP of the instance was set.
+	if instance.FloatingIP != "" && instance.FloatingIP != fp.FloatingIP && isBastionFloatingIPManaged(openStackCluster, instance.FloatingIP) {
+		if err = networkingService.DeleteFloatingIP(openStackCluster, instance.FloatingIP); err != nil {
+			return errors.Errorf("failed to delete previous floating IP of bastion: %v", err)
+		}
 	}
 	err = computeService.AssociateFloatingIP(instance.ID, fp.FloatingIP)
 	if err != nil {
@@ -330,9 +504,102 @@ func reconcileBastion(log logr.Logger, osProviderClient *gophercloud.ProviderCli
 	}
 	instance.FloatingIP = fp.FloatingIP
 	openStackCluster.Status.Bastion = instance
+	openStackCluster.Status.ManagedResources.BastionFloatingIPID = managedFloatingIPID
 	return nil
 }
 
+// bastionFloatingIP returns the floating IP of the instance of the bastion,
+// which is provided by the user and therefore never allocated nor released.
+func bastionFloatingIP(openStackCluster *infrav1.OpenStackCluster) string {
+	if openStackCluster.Spec.Bastion == nil {
+		return ""
+	}
+	return openStackCluster.Spec.Bastion.Instance.FloatingIP
+}
+
+// isBastionFloatingIPManaged reports whether the floating IP of the bastion
+// was allocated for it, and may therefore be released. This is the floating IP
+// recorded in the status of the bastion, unless it is the floating IP of the
+// instance of the bastion.
+func isBastionFloatingIPManaged(openStackCluster *infrav1.OpenStackCluster, floatingIP string) bool {
+	if status := openStackCluster.Status.Bastion; status == nil || status.FloatingIP != floatingIP {
+		return false
+	}
+	return floatingIP != bastionFloatingIP(openStackCluster)
+}
+
+// updateManagedResources records the IDs of the created resources which are
+// known from the status of the cluster.
+func updateManagedResources(openStackCluster *infrav1.OpenStackCluster) {
+	managedResources := openStackCluster.Status.ManagedResources
+
+	// if neither NodeCIDR nor NodeSubnetPool was set, no network was created.
+	if network := openStackCluster.Status.Network; network != nil && isNetworkManaged(openStackCluster) {
+		managedResources.NetworkID = network.ID
+		managedResources.SubnetIDs = nil
+		if network.Subnet != nil {
+			managedResources.SubnetIDs = []string{network.Subnet.ID}
+		}
+		if network.IPv6Subnet != nil {
+			managedResources.SubnetIDs = append(managedResources.SubnetIDs, network.IPv6Subnet.ID)
+		}
+		managedResources.RouterID = ""
+		if network.Router != nil {
+			managedResources.RouterID = network.Router.ID
+		}
+	}
+
+	managedResources.SecurityGroupIDs = nil
+	for _, secGroup := range []*infrav1.SecurityGroup{
+		openStackCluster.Status.ControlPlaneSecurityGroup,
+		openStackCluster.Status.WorkerSecurityGroup,
+		openStackCluster.Status.BastionSecurityGroup,
+	} {
+		if secGroup != nil {
+			managedResources.SecurityGroupIDs = append(managedResources.SecurityGroupIDs, secGroup.ID)
+		}
+	}
+
+	managedResources.BastionID = ""
+	if openStackCluster.Status.Bastion != nil {
+		managedResources.BastionID = openStackCluster.Status.Bastion.ID
+	}
+}
+
+// apiServerPort returns the port of the ControlPlaneEndpoint.
+func apiServerPort(openStackCluster *infrav1.OpenStackCluster) int32 {
+	if openStackCluster.Spec.APIServerPort == 0 {
+		return 6443
+	}
+	return int32(openStackCluster.Spec.APIServerPort)
+}
+
+// validateUnmanagedNetworking returns an error if the spec of a cluster with
+// DisableManagedNetworking requires networking resources to be created.
+func validateUnmanagedNetworking(openStackCluster *infrav1.OpenStackCluster) error {
+	if isNetworkManaged(openStackCluster) {
+		return errors.New("nodeCidr and nodeSubnetPool cannot be set with disableManagedNetworking")
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		return errors.New("managedSecurityGroups cannot be set with disableManagedNetworking")
+	}
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer {
+		return errors.New("managedAPIServerLoadBalancer cannot be set with disableManagedNetworking")
+	}
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		return errors.New("managedAPIServerVIP cannot be set with disableManagedNetworking")
+	}
+	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+		return errors.New("controlPlaneEndpoint has to be set with disableManagedNetworking")
+	}
+	return nil
+}
+
+// isNetworkManaged returns true if the network of the cluster is created by the controller.
+func isNetworkManaged(openStackCluster *infrav1.OpenStackCluster) bool {
+	return openStackCluster.Spec.NodeCIDR != "" || openStackCluster.Spec.NodeSubnetPool != nil
+}
+
 func reconcileNetworkComponents(log logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
 	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
 
@@ -348,12 +615,28 @@ func reconcileNetworkComponents(log logr.Logger, osProviderClient *gophercloud.P
 
 	log.Info("Reconciling network components")
 
-	err = networkingService.ReconcileExternalNetwork(openStackCluster)
-	if err != nil {
-		return errors.Errorf("failed to reconcile external network: %v", err)
+	if openStackCluster.Spec.NodeCIDR != "" && openStackCluster.Spec.NodeSubnetPool != nil {
+		return errors.New("nodeCidr and nodeSubnetPool are mutually exclusive")
+	}
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer && openStackCluster.Spec.ManagedAPIServerVIP {
+		return errors.New("managedAPIServerLoadBalancer and managedAPIServerVIP are mutually exclusive")
+	}
+	if openStackCluster.Spec.APIServerLoadBalancerID != "" && !openStackCluster.Spec.ManagedAPIServerLoadBalancer {
+		return errors.New("apiServerLoadBalancerID requires managedAPIServerLoadBalancer")
 	}
 
-	if openStackCluster.Spec.NodeCIDR == "" {
+	if openStackCluster.Spec.DisableManagedNetworking {
+		if err := validateUnmanagedNetworking(openStackCluster); err != nil {
+			return err
+		}
+	} else {
+		err = networkingService.ReconcileExternalNetwork(openStackCluster)
+		if err != nil {
+			return errors.Errorf("failed to reconcile external network: %v", err)
+		}
+	}
+
+	if !isNetworkManaged(openStackCluster) {
 		log.V(4).Info("No need to reconcile network, searching network and subnet instead")
 
 		netOpts := networks.ListOpts(openStackCluster.Spec.Network)
@@ -388,6 +671,18 @@ func reconcileNetworkComponents(log logr.Logger, osProviderClient *gophercloud.P
 			CIDR: subnetList[0].CIDR,
 			Tags: subnetList[0].Tags,
 		}
+
+		if openStackCluster.Spec.Router != nil {
+			router, err := networkingService.GetRouterByFilter(openStackCluster.Spec.Router)
+			if err != nil {
+				return errors.Errorf("failed to find router: %v", err)
+			}
+			openStackCluster.Status.Network.Router = &infrav1.Router{
+				ID:   router.ID,
+				Name: router.Name,
+				Tags: router.Tags,
+			}
+		}
 	} else {
 		err := networkingService.ReconcileNetwork(openStackCluster, clusterName)
 		if err != nil {
@@ -403,20 +698,30 @@ func reconcileNetworkComponents(log logr.Logger, osProviderClient *gophercloud.P
 		}
 	}
 	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
-		var port int32
-		if openStackCluster.Spec.APIServerPort == 0 {
-			port = 6443
-		} else {
-			port = int32(openStackCluster.Spec.APIServerPort)
-		}
-		fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster.Spec.APIServerFloatingIP)
-		if err != nil {
-			return errors.Errorf("Floating IP cannot be got or created: %v", err)
+		var host string
+		switch {
+		case openStackCluster.Spec.DisableAPIServerFloatingIP && openStackCluster.Spec.ManagedAPIServerLoadBalancer:
+			// The endpoint is the VIP of the load balancer, which is set below.
+		case openStackCluster.Spec.ManagedAPIServerVIP:
+			// The endpoint is the address of the VIP port, which is set below.
+		case openStackCluster.Spec.DisableAPIServerFloatingIP:
+			if openStackCluster.Spec.APIServerFixedIP == "" {
+				return errors.New("apiServerFixedIP has to be set with disableAPIServerFloatingIP and without managedAPIServerLoadBalancer")
+			}
+			host = openStackCluster.Spec.APIServerFixedIP
+		default:
+			fp, err := networkingService.GetOrCreateAPIServerFloatingIP(openStackCluster, openStackCluster.Spec.APIServerFloatingIP)
+			if err != nil {
+				return errors.Errorf("Floating IP cannot be got or created: %v", err)
+			}
+			host = fp.FloatingIP
 		}
-		// Set APIEndpoints so the Cluster API Cluster Controller can pull them
-		openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-			Host: fp.FloatingIP,
-			Port: port,
+		if host != "" {
+			// Set APIEndpoints so the Cluster API Cluster Controller can pull them
+			openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+				Host: host,
+				Port: apiServerPort(openStackCluster),
+			}
 		}
 	}
 
@@ -430,6 +735,31 @@ func reconcileNetworkComponents(log logr.Logger, osProviderClient *gophercloud.P
 		if err != nil {
 			return errors.Errorf("failed to reconcile load balancer: %v", err)
 		}
+
+		if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+			openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+				Host: openStackCluster.Status.Network.APIServerLoadBalancer.InternalIP,
+				Port: apiServerPort(openStackCluster),
+			}
+		}
+	}
+
+	if openStackCluster.Spec.ManagedAPIServerVIP {
+		err = networkingService.ReconcileAPIServerVIP(openStackCluster, clusterName)
+		if err != nil {
+			return errors.Errorf("failed to reconcile API server VIP: %v", err)
+		}
+
+		if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+			host := openStackCluster.Status.APIServerVIP.FloatingIP
+			if host == "" {
+				host = openStackCluster.Status.APIServerVIP.Address
+			}
+			openStackCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+				Host: host,
+				Port: apiServerPort(openStackCluster),
+			}
+		}
 	}
 
 	return nil
@@ -20,7 +20,10 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -36,6 +39,7 @@ import (
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -49,10 +53,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/capabilities"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/dns"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/servergroups"
 )
 
 // OpenStackMachineReconciler reconciles a OpenStackMachine object.
@@ -60,10 +67,26 @@ type OpenStackMachineReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// ComputeHostCheckInterval is the interval at which the compute service
+	// hosting an active instance is checked. Zero disables the check.
+	ComputeHostCheckInterval time.Duration
+	// InstanceCheckInterval is the interval at which the existence of the
+	// instance is checked. Zero disables the check.
+	InstanceCheckInterval time.Duration
+	// EnableInstanceResize enables resizing the instances of machines with
+	// ResizeOnFlavorChange when their flavor changes.
+	EnableInstanceResize bool
 }
 
 const (
 	waitForClusterInfrastructureReadyDuration = 15 * time.Second
+	waitForBootstrapDuration                  = 15 * time.Second
+	instanceCreateRetryBaseDelay              = 30 * time.Second
+	instanceCreateRetryMaxDelay               = 10 * time.Minute
+	bootstrapCheckDialTimeout                 = 5 * time.Second
+	// consoleLogEventSize is the maximum number of bytes of the console log
+	// which are attached to an event.
+	consoleLogEventSize = 1024
 )
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,verbs=get;list;watch;create;update;patch;delete
@@ -214,13 +237,29 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, logger
 		}
 	}
 
-	instance, err := computeService.InstanceExists(openStackMachine.Name)
+	if openStackCluster.Spec.NodeDNSRecords != nil {
+		dnsService, err := dns.NewService(osProviderClient, clientOpts, logger)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err = dnsService.DeleteInstanceRecords(openStackMachine, openStackCluster.Spec.NodeDNSRecords); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "DNS records cannot be deleted")
+		}
+	}
+
+	instance, err := getInstance(openStackCluster, openStackMachine, computeService)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if instance == nil {
 		logger.Info("Skipped deleting machine that is already deleted")
+		if err = computeService.DeleteOrphanedPorts(openStackMachine, openStackCluster.Spec.Timeouts); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "orphaned ports cannot be deleted")
+		}
+		if err = networkingService.DeleteMachineFloatingIP(openStackMachine); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "floating IP cannot be deleted")
+		}
 		controllerutil.RemoveFinalizer(openStackMachine, infrav1.MachineFinalizer)
 		if err := patchHelper.Patch(ctx, openStackMachine); err != nil {
 			return ctrl.Result{}, err
@@ -228,16 +267,53 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, logger
 		return ctrl.Result{}, nil
 	}
 
-	err = computeService.InstanceDelete(machine, openStackMachine)
-	if err != nil {
-		handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error deleting Openstack instance: %v", err))
+	if openStackMachine.Spec.ShelveOnDelete != nil {
+		err = computeService.InstanceShelve(openStackCluster, openStackMachine, instance, clusterName)
+		if err != nil {
+			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error shelving Openstack instance: %v", err))
+			return ctrl.Result{}, nil
+		}
+	} else {
+		err = computeService.InstanceDelete(openStackCluster, machine, openStackMachine)
+		if err != nil {
+			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error deleting Openstack instance: %v", err))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if !openStackCluster.Spec.ManagedAPIServerLoadBalancer && !openStackCluster.Spec.ManagedAPIServerVIP && !openStackCluster.Spec.DisableAPIServerFloatingIP &&
+		util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" && instance.FloatingIP != "" {
+		// The own floating IP of the machine is released below, if it was
+		// allocated for it.
+		machineFloatingIP, err := networkingService.IsMachineFloatingIP(openStackMachine, instance.FloatingIP)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !machineFloatingIP {
+			if err = networkingService.DeleteFloatingIP(openStackCluster, instance.FloatingIP); err != nil {
+				handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error deleting Openstack floating IP: %v", err))
+				return ctrl.Result{}, nil
+			}
+		}
+	}
+
+	if err = networkingService.DeleteMachineFloatingIP(openStackMachine); err != nil {
+		handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error deleting Openstack floating IP: %v", err))
 		return ctrl.Result{}, nil
 	}
 
-	if !openStackCluster.Spec.ManagedAPIServerLoadBalancer && util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" && instance.FloatingIP != "" {
-		if err = networkingService.DeleteFloatingIP(openStackCluster, instance.FloatingIP); err != nil {
-			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("error deleting Openstack floating IP: %v", err))
-			return ctrl.Result{}, nil
+	if err = computeService.DeleteOrphanedPorts(openStackMachine, openStackCluster.Spec.Timeouts); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "orphaned ports cannot be deleted")
+	}
+
+	// Delete the managed server group once its last member is gone.
+	if openStackMachine.Status.ServerGroupID != "" {
+		serverGroupService, err := servergroups.NewService(osProviderClient, clientOpts, logger)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err = serverGroupService.DeleteServerGroupIfEmpty(openStackMachine, openStackMachine.Status.ServerGroupID); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "server group cannot be deleted")
 		}
 	}
 
@@ -250,8 +326,11 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, logger
 }
 
 func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, logger logr.Logger, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (_ ctrl.Result, reterr error) {
-	// If the OpenStackMachine is in an error state, return early.
-	if openStackMachine.Status.FailureReason != nil || openStackMachine.Status.FailureMessage != nil {
+	// If the OpenStackMachine is in an error state, return early. A machine on a
+	// down compute host is still checked, so that the failure is cleared once the
+	// host is up again.
+	if (openStackMachine.Status.FailureReason != nil || openStackMachine.Status.FailureMessage != nil) &&
+		conditions.GetReason(openStackMachine, infrav1.InstanceReadyCondition) != infrav1.ComputeHostDownReason {
 		logger.Info("Error state detected, skipping reconciliation")
 		return ctrl.Result{}, nil
 	}
@@ -296,8 +375,41 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, logger
 		return ctrl.Result{}, err
 	}
 
+	// Don't recreate an instance which was deleted outside of Kubernetes.
+	if r.InstanceCheckInterval > 0 && openStackMachine.Spec.InstanceID != nil && openStackMachine.Spec.ProviderID != nil {
+		exists, err := computeService.InstanceIDExists(*openStackMachine.Spec.InstanceID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !exists {
+			err := errors.Errorf("OpenStack instance %s has been deleted outside of Kubernetes", *openStackMachine.Spec.InstanceID)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceDeletedReason, clusterv1.ConditionSeverityError, err.Error())
+			r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "InstanceDeleted", err.Error())
+			handleUpdateMachineError(logger, openStackMachine, err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Check the capabilities of the cloud before the instance is created.
+	if openStackMachine.Spec.InstanceID == nil {
+		if err := r.reconcileCompatibility(logger, osProviderClient, clientOpts, openStackMachine); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Assign the machine to its managed server group before the instance is created.
+	if (openStackCluster.Spec.ManagedServerGroups || openStackMachine.Spec.ServerGroupPolicy != "") && openStackMachine.Spec.InstanceID == nil &&
+		openStackMachine.Spec.ServerGroupID == "" && openStackMachine.Spec.ServerGroupName == "" {
+		if err := r.reconcileServerGroup(logger, osProviderClient, clientOpts, clusterName, machine, openStackMachine); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	instance, err := r.getOrCreate(logger, cluster, openStackCluster, machine, openStackMachine, computeService, userData)
 	if err != nil {
+		if requeueAfter, retry := r.retryInstanceCreate(logger, openStackCluster, openStackMachine, computeService, err); retry {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
 		handleUpdateMachineError(logger, openStackMachine, errors.Errorf("OpenStack instance cannot be created: %v", err))
 		return ctrl.Result{}, err
 	}
@@ -308,17 +420,56 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, logger
 		return ctrl.Result{}, nil
 	}
 
+	// Access the instance by its IPv6 address, if requested.
+	if openStackMachine.Spec.AccessIPFamily == infrav1.IPFamilyIPv6 && instance.IPv6 != "" {
+		instance.IP = instance.IPv6
+	}
+
+	computeService.LogInstanceDiff(openStackCluster, openStackMachine, instance)
+
 	// TODO(sbueringer) From CAPA: TODO(ncdc): move this validation logic into a validating webhook (for us: create validation logic in webhook)
 
 	openStackMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("openstack:///%s", instance.ID))
 	openStackMachine.Spec.InstanceID = pointer.StringPtr(instance.ID)
 
 	openStackMachine.Status.InstanceState = &instance.State
+	openStackMachine.Status.AvailabilityZone = instance.AvailabilityZone
+	openStackMachine.Status.HostID = instance.HostID
+	openStackMachine.Status.Hypervisor = instance.Hypervisor
+	openStackMachine.Status.LaunchedAt = instance.LaunchedAt
+
+	if openStackMachine.Spec.AssociateFloatingIP && instance.State == infrav1.InstanceStateActive {
+		fp, err := networkingService.GetOrCreateMachineFloatingIP(openStackCluster, openStackMachine)
+		if err != nil {
+			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("Floating IP cannot be got or created: %v", err))
+			return ctrl.Result{}, nil
+		}
+		if instance.FloatingIP != fp.FloatingIP {
+			portID, err := networkingService.GetInstancePortID(instance.ID, instance.IP)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if portID == "" {
+				return ctrl.Result{}, errors.Errorf("no port with address %s found to associate floating IP %s with", instance.IP, fp.FloatingIP)
+			}
+			if err = networkingService.AssociateFloatingIP(openStackCluster, fp, portID); err != nil {
+				handleUpdateMachineError(logger, openStackMachine, errors.Errorf("Floating IP cannot be associated: %v", err))
+				return ctrl.Result{}, nil
+			}
+			instance.FloatingIP = fp.FloatingIP
+		}
+	}
 
 	address := []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: instance.IP}}
 	if instance.FloatingIP != "" {
 		address = append(address, []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: instance.FloatingIP}}...)
 	}
+	// Add the addresses of the other ports of the instance after the primary ones.
+	for _, a := range instance.Addresses {
+		if !containsAddress(address, a) {
+			address = append(address, a)
+		}
+	}
 	openStackMachine.Status.Addresses = address
 
 	// TODO(sbueringer) From CAPA: TODO(vincepri): Remove this annotation when clusterctl is no longer relevant.
@@ -327,25 +478,108 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, logger
 	}
 	openStackMachine.Annotations["cluster-api-provider-openstack"] = "true"
 
+	if openStackMachine.Spec.InstanceHA {
+		failure, err := computeService.InstanceHAFailure(instance.ID, openStackMachine.CreationTimestamp.Time)
+		if err != nil {
+			logger.Info("Failed to get instance HA notifications", "instance-id", instance.ID, "error", err.Error())
+		} else if failure != "" {
+			err := errors.Errorf("Masakari failed to recover OpenStack instance: %s", failure)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceHARecoveryFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "InstanceHARecoveryFailed", err.Error())
+			handleUpdateMachineError(logger, openStackMachine, err)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	switch instance.State {
 	case infrav1.InstanceStateActive:
 		logger.Info("Machine instance is ACTIVE", "instance-id", instance.ID)
+		if host, down := r.isComputeHostDown(logger, computeService, instance); down {
+			err := errors.Errorf("compute service on host %q of OpenStack instance is down", host)
+			openStackMachine.Status.Ready = false
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.ComputeHostDownReason, clusterv1.ConditionSeverityError, err.Error())
+			r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "ComputeHostDown", err.Error())
+			// The failure reason is propagated to the Machine, so that a
+			// MachineHealthCheck remediates it. It is cleared again if the host
+			// is up before the machine is remediated.
+			handleUpdateMachineError(logger, openStackMachine, err)
+			return ctrl.Result{RequeueAfter: r.ComputeHostCheckInterval}, nil
+		}
+		if conditions.GetReason(openStackMachine, infrav1.InstanceReadyCondition) == infrav1.ComputeHostDownReason {
+			logger.Info("Compute host of machine instance is up again", "instance-id", instance.ID)
+			r.Recorder.Event(openStackMachine, corev1.EventTypeNormal, "ComputeHostUp", "Compute service on host of OpenStack instance is up again")
+			openStackMachine.Status.FailureReason = nil
+			openStackMachine.Status.FailureMessage = nil
+		}
 		openStackMachine.Status.Ready = true
+		conditions.MarkTrue(openStackMachine, infrav1.InstanceReadyCondition)
 	case infrav1.InstanceStateBuilding:
 		logger.Info("Machine instance is BUILDING", "instance-id", instance.ID)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "")
+	case infrav1.InstanceStateRebuild:
+		logger.Info("Machine instance is REBUILD", "instance-id", instance.ID)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "")
+	case infrav1.InstanceStateResize, infrav1.InstanceStateVerifyResize:
+		logger.Info("Machine instance is "+string(instance.State), "instance-id", instance.ID)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "")
 	default:
-		handleUpdateMachineError(logger, openStackMachine, errors.Errorf("OpenStack instance state %q is unexpected", instance.State))
+		err := errors.Errorf("OpenStack instance state %q is unexpected", instance.State)
+		if instance.State == infrav1.InstanceStateError {
+			if fault := computeService.InstanceFault(instance.ID); fault != "" {
+				err = errors.Errorf("%v: %s", err, fault)
+			}
+		}
+		if failure := computeService.InstanceActionFailure(instance.ID); failure != "" {
+			err = errors.Errorf("%v: %s", err, failure)
+		}
+		// A server which went into ERROR state before it ever became ready,
+		// e.g. found after a restart of the controller, is created again too.
+		if instance.State == infrav1.InstanceStateError && !openStackMachine.Status.Ready {
+			if requeueAfter, retry := r.retryInstanceCreate(logger, openStackCluster, openStackMachine, computeService, err); retry {
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
+		}
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStateErrorReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "UnexpectedInstanceState", err.Error())
+		handleUpdateMachineError(logger, openStackMachine, err)
 		return ctrl.Result{}, nil
 	}
 
+	// Keep the metadata and the tags of the server in sync with the machine.
+	if instance.State == infrav1.InstanceStateActive {
+		if err := computeService.ReconcileInstanceMetadataAndTags(openStackCluster, openStackMachine, instance); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "instance metadata and tags cannot be reconciled")
+		}
+		if err := computeService.ReconcileInstanceSecurityGroups(openStackCluster, machine, openStackMachine, instance); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "instance security groups cannot be reconciled")
+		}
+	}
+
+	// Rebuild the server in place if the image of the machine was changed.
+	if openStackMachine.Spec.RebuildOnImageChange && instance.State == infrav1.InstanceStateActive {
+		instance, err = computeService.ReconcileInstanceImage(openStackCluster, openStackMachine, instance)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "instance cannot be rebuilt")
+		}
+	}
+
+	// Resize the server if the flavor of the machine was changed.
+	if r.EnableInstanceResize && openStackMachine.Spec.ResizeOnFlavorChange &&
+		(instance.State == infrav1.InstanceStateActive || instance.State == infrav1.InstanceStateVerifyResize) {
+		instance, err = computeService.ReconcileInstanceFlavor(openStackCluster, openStackMachine, instance)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "instance cannot be resized")
+		}
+	}
+
 	if openStackCluster.Spec.ManagedAPIServerLoadBalancer {
 		err = r.reconcileLoadBalancerMember(logger, osProviderClient, clientOpts, openStackCluster, machine, openStackMachine, instance, clusterName)
 		if err != nil {
 			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("LoadBalancerMember cannot be reconciled: %v", err))
 			return ctrl.Result{}, nil
 		}
-	} else if util.IsControlPlaneMachine(machine) {
-		fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster.Spec.ControlPlaneEndpoint.Host)
+	} else if util.IsControlPlaneMachine(machine) && !openStackCluster.Spec.DisableManagedNetworking && !openStackCluster.Spec.DisableAPIServerFloatingIP && !openStackCluster.Spec.ManagedAPIServerVIP {
+		fp, err := networkingService.GetOrCreateAPIServerFloatingIP(openStackCluster, openStackCluster.Spec.ControlPlaneEndpoint.Host)
 		if err != nil {
 			handleUpdateMachineError(logger, openStackMachine, errors.Errorf("Floating IP cannot be got or created: %v", err))
 			return ctrl.Result{}, nil
@@ -357,16 +591,241 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, logger
 		}
 	}
 
+	if openStackCluster.Spec.NodeDNSRecords != nil && instance.State == infrav1.InstanceStateActive {
+		err = r.reconcileDNSRecords(logger, osProviderClient, clientOpts, openStackCluster, openStackMachine, instance)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "DNS records cannot be reconciled")
+		}
+	}
+
+	if instance.State == infrav1.InstanceStateActive && openStackMachine.Spec.BootstrapCheck != nil {
+		succeeded, err := r.reconcileBootstrapCheck(logger, osProviderClient, clientOpts, openStackCluster, machine, openStackMachine, instance, computeService, clusterName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !succeeded {
+			return ctrl.Result{RequeueAfter: waitForBootstrapDuration}, nil
+		}
+	}
+
 	logger.Info("Reconciled Machine create successfully")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.checkInterval()}, nil
+}
+
+// checkBootstrapPort returns whether the port of the bootstrap check accepts
+// connections. The API server port of a control plane machine is checked
+// through the API server load balancer, as the controller usually can't reach
+// the machine network. Other ports are probed on the address of the machine.
+func (r *OpenStackMachineReconciler) checkBootstrapPort(logger logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance, clusterName string, port int) (bool, error) {
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer && util.IsControlPlaneMachine(machine) && port == int(openStackCluster.Spec.ControlPlaneEndpoint.Port) {
+		loadbalancerService, err := loadbalancer.NewService(osProviderClient, clientOpts, logger)
+		if err != nil {
+			return false, err
+		}
+		return loadbalancerService.IsLoadBalancerMemberOnline(openStackCluster, openStackMachine, clusterName, port)
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(instance.IP, strconv.Itoa(port)), bootstrapCheckDialTimeout)
+	if err != nil {
+		logger.V(4).Info("Bootstrap check port doesn't accept connections yet", "address", instance.IP, "port", port, "error", err.Error())
+		return false, nil
+	}
+	conn.Close()
+	return true, nil
+}
+
+// reconcileBootstrapCheck keeps the machine not ready until its bootstrap check
+// passed and marks it as failed if the check doesn't pass in time.
+func (r *OpenStackMachineReconciler) reconcileBootstrapCheck(logger logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance, computeService *compute.Service, clusterName string) (bool, error) {
+	if conditions.IsTrue(openStackMachine, infrav1.BootstrapSucceededCondition) {
+		return true, nil
+	}
+
+	check := openStackMachine.Spec.BootstrapCheck
+	succeeded := false
+	if check.MetadataKey != "" {
+		metadata, err := computeService.GetInstanceMetadata(instance.ID)
+		if err != nil {
+			return false, err
+		}
+		_, succeeded = metadata[check.MetadataKey]
+	} else {
+		var err error
+		succeeded, err = r.checkBootstrapPort(logger, osProviderClient, clientOpts, openStackCluster, machine, openStackMachine, instance, clusterName, check.Port)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if succeeded {
+		logger.Info("Machine instance bootstrap succeeded", "instance-id", instance.ID)
+		conditions.MarkTrue(openStackMachine, infrav1.BootstrapSucceededCondition)
+		return true, nil
+	}
+
+	openStackMachine.Status.Ready = false
+	// The instance is waiting for its bootstrap since it became active.
+	if check.Timeout != nil {
+		if active := conditions.GetLastTransitionTime(openStackMachine, infrav1.InstanceReadyCondition); active != nil && time.Since(active.Time) > check.Timeout.Duration {
+			err := errors.Errorf("bootstrap of OpenStack instance didn't succeed within %s", check.Timeout.Duration)
+			conditions.MarkFalse(openStackMachine, infrav1.BootstrapSucceededCondition, infrav1.BootstrapTimeoutReason, clusterv1.ConditionSeverityError, err.Error())
+			r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "BootstrapTimeout", err.Error())
+			if check.ConsoleLogLines > 0 {
+				r.recordConsoleLog(logger, openStackMachine, instance, computeService, check.ConsoleLogLines)
+			}
+			handleUpdateMachineError(logger, openStackMachine, err)
+			return false, nil
+		}
+	}
+	logger.Info("Waiting for machine instance bootstrap", "instance-id", instance.ID)
+	conditions.MarkFalse(openStackMachine, infrav1.BootstrapSucceededCondition, infrav1.WaitingForBootstrapReason, clusterv1.ConditionSeverityInfo, "")
+	return false, nil
+}
+
+// recordConsoleLog logs the console log of the instance for the diagnosis of a
+// failed bootstrap and attaches its end to an event of the machine. Capturing
+// the console log is best effort.
+func (r *OpenStackMachineReconciler) recordConsoleLog(logger logr.Logger, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance, computeService *compute.Service, lines int) {
+	output, err := computeService.GetInstanceConsoleLog(instance.ID, lines)
+	if err != nil {
+		logger.Info("Failed to get console log of machine instance", "instance-id", instance.ID, "error", err.Error())
+		return
+	}
+	logger.Info("Console log of machine instance", "instance-id", instance.ID, "output", output)
+
+	if len(output) > consoleLogEventSize {
+		output = "..." + output[len(output)-consoleLogEventSize:]
+	}
+	r.Recorder.Eventf(openStackMachine, corev1.EventTypeWarning, "BootstrapConsoleLog", "Console log of OpenStack instance %s:\n%s", instance.ID, output)
+}
+
+// checkInterval returns the shortest enabled interval at which a reconciled
+// machine has to be checked again, zero if no check is enabled.
+func (r *OpenStackMachineReconciler) checkInterval() time.Duration {
+	interval := r.ComputeHostCheckInterval
+	if r.InstanceCheckInterval > 0 && (interval == 0 || r.InstanceCheckInterval < interval) {
+		interval = r.InstanceCheckInterval
+	}
+	return interval
+}
+
+func (r *OpenStackMachineReconciler) reconcileCompatibility(logger logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, openStackMachine *infrav1.OpenStackMachine) error {
+	capabilitiesService, err := capabilities.NewService(osProviderClient, clientOpts, logger)
+	if err != nil {
+		return err
+	}
+
+	missing, err := capabilitiesService.MissingMachineCapabilities(&openStackMachine.Spec)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the capabilities of the cloud")
+	}
+	if len(missing) > 0 {
+		message := fmt.Sprintf("the cloud doesn't provide: %s", strings.Join(missing, ", "))
+		conditions.MarkFalse(openStackMachine, infrav1.InfrastructureCompatibleCondition, infrav1.MissingCapabilitiesReason, clusterv1.ConditionSeverityError, message)
+		return errors.New(message)
+	}
+	conditions.MarkTrue(openStackMachine, infrav1.InfrastructureCompatibleCondition)
+	return nil
+}
+
+func (r *OpenStackMachineReconciler) reconcileServerGroup(logger logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, clusterName string, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) error {
+	if openStackMachine.Status.ServerGroupID != "" {
+		return nil
+	}
+
+	serverGroupService, err := servergroups.NewService(osProviderClient, clientOpts, logger)
+	if err != nil {
+		return err
+	}
+
+	policy := openStackMachine.Spec.ServerGroupPolicy
+	if policy == "" {
+		policy = infrav1.ServerGroupPolicySoftAntiAffinity
+	}
+	serverGroupID, err := serverGroupService.GetOrCreateServerGroup(openStackMachine, servergroups.ServerGroupName(clusterName, machine, string(policy)), string(policy))
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile server group")
+	}
+	openStackMachine.Status.ServerGroupID = serverGroupID
+	return nil
+}
+
+// isComputeHostDown reports whether the compute service on the host of the
+// instance is down, if the compute host check is enabled.
+func (r *OpenStackMachineReconciler) isComputeHostDown(logger logr.Logger, computeService *compute.Service, instance *infrav1.Instance) (string, bool) {
+	if r.ComputeHostCheckInterval == 0 {
+		return "", false
+	}
+	host, down, err := computeService.IsComputeHostDown(instance.ID)
+	if err != nil {
+		// The check is best effort, e.g. the credentials may lack the admin role.
+		logger.Info("Failed to check compute host of instance", "instance-id", instance.ID, "error", err.Error())
+		return "", false
+	}
+	return host, down
+}
+
+// retryInstanceCreate deletes the server of the machine if it went into ERROR
+// state while it was created and retries are left. It returns the delay after
+// which the server is created again.
+func (r *OpenStackMachineReconciler) retryInstanceCreate(logger logr.Logger, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service, createErr error) (time.Duration, bool) {
+	if openStackMachine.Status.InstanceCreateRetryCount >= openStackMachine.Spec.InstanceCreateRetries {
+		return 0, false
+	}
+
+	instance, err := getInstance(openStackCluster, openStackMachine, computeService)
+	if err != nil || instance == nil || instance.State != infrav1.InstanceStateError {
+		return 0, false
+	}
+	// An adopted server was not created by us, so it is not created again.
+	if instance.Name != compute.InstanceName(openStackMachine) {
+		return 0, false
+	}
+	if err := computeService.DeleteFailedInstance(openStackCluster, openStackMachine, instance); err != nil {
+		logger.Info("Failed to delete instance in ERROR state", "instance-id", instance.ID, "error", err.Error())
+		return 0, false
+	}
+	// The next server gets a new ID.
+	if openStackMachine.Spec.InstanceID != nil && *openStackMachine.Spec.InstanceID == instance.ID {
+		openStackMachine.Spec.InstanceID = nil
+		openStackMachine.Spec.ProviderID = nil
+	}
+
+	openStackMachine.Status.InstanceCreateRetryCount++
+	delay := instanceCreateRetryBaseDelay
+	for i := 1; i < openStackMachine.Status.InstanceCreateRetryCount && delay < instanceCreateRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > instanceCreateRetryMaxDelay {
+		delay = instanceCreateRetryMaxDelay
+	}
+	message := fmt.Sprintf("OpenStack instance cannot be created, retry %d of %d in %s: %v", openStackMachine.Status.InstanceCreateRetryCount, openStackMachine.Spec.InstanceCreateRetries, delay, createErr)
+	conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateRetryReason, clusterv1.ConditionSeverityWarning, message)
+	r.Recorder.Event(openStackMachine, corev1.EventTypeWarning, "InstanceCreateRetry", message)
+	return delay, true
 }
 
 func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service, userData string) (*infrav1.Instance, error) {
-	instance, err := computeService.InstanceExists(openStackMachine.Name)
+	instance, err := computeService.InstanceExists(compute.InstanceName(openStackMachine), compute.ClusterInstanceTags(openStackCluster))
 	if err != nil {
 		return nil, err
 	}
 
+	// Adopt an existing server, which doesn't carry the name of the machine.
+	if instance == nil && openStackMachine.Spec.InstanceID != nil {
+		instance, err = computeService.GetInstance(*openStackMachine.Spec.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if instance != nil && openStackMachine.Spec.ProviderID == nil {
+			if instance.IP == "" {
+				return nil, errors.Errorf("OpenStack instance %s to adopt has no address", instance.ID)
+			}
+			logger.Info("Adopting existing instance", "instance-id", instance.ID)
+			r.Recorder.Eventf(openStackMachine, corev1.EventTypeNormal, "AdoptedInstance", "Adopted existing instance %s with id %s", instance.Name, instance.ID)
+		}
+	}
+
 	if instance == nil {
 		logger.Info("Machine not exist, Creating Machine", "Machine", openStackMachine.Name)
 		instance, err = computeService.InstanceCreate(openStackCluster, machine, openStackMachine, cluster.Name, userData)
@@ -378,6 +837,25 @@ func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *cl
 	return instance, nil
 }
 
+// getInstance returns the server of the machine. An adopted server, which
+// doesn't carry the name of the machine, is found by the InstanceID of the spec.
+func getInstance(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service) (*infrav1.Instance, error) {
+	instance, err := computeService.InstanceExists(compute.InstanceName(openStackMachine), compute.ClusterInstanceTags(openStackCluster))
+	if err != nil || instance != nil || openStackMachine.Spec.InstanceID == nil {
+		return instance, err
+	}
+	return computeService.GetInstance(*openStackMachine.Spec.InstanceID)
+}
+
+func containsAddress(addresses []corev1.NodeAddress, address corev1.NodeAddress) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
 func handleUpdateMachineError(logger logr.Logger, openstackMachine *infrav1.OpenStackMachine, message error) {
 	err := capierrors.UpdateMachineError
 	openstackMachine.Status.FailureReason = &err
@@ -396,6 +874,19 @@ func (r *OpenStackMachineReconciler) reconcileLoadBalancerMember(logger logr.Log
 	return loadbalancerService.ReconcileLoadBalancerMember(openStackCluster, machine, openStackMachine, clusterName, ip)
 }
 
+func (r *OpenStackMachineReconciler) reconcileDNSRecords(logger logr.Logger, osProviderClient *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) error {
+	addresses := []string{instance.IP}
+	if instance.FloatingIP != "" {
+		addresses = append(addresses, instance.FloatingIP)
+	}
+	dnsService, err := dns.NewService(osProviderClient, clientOpts, logger)
+	if err != nil {
+		return err
+	}
+
+	return dnsService.ReconcileInstanceRecords(openStackMachine, openStackCluster.Spec.NodeDNSRecords, addresses)
+}
+
 // OpenStackClusterToOpenStackMachine is a handler.ToRequestsFunc to be used to enqeue requests for reconciliation
 // of OpenStackMachines.
 func (r *OpenStackMachineReconciler) OpenStackClusterToOpenStackMachines(log logr.Logger) handler.MapFunc {
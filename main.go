@@ -60,6 +60,10 @@ var (
 	openStackClusterConcurrency int
 	openStackMachineConcurrency int
 	syncPeriod                  time.Duration
+	computeHostCheckInterval    time.Duration
+	instanceCheckInterval       time.Duration
+	loadBalancerMetricsInterval time.Duration
+	enableInstanceResize        bool
 	webhookPort                 int
 	webhookCertDir              string
 	healthAddr                  string
@@ -109,6 +113,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
+	fs.DurationVar(&computeHostCheckInterval, "compute-host-check-interval", 0,
+		"Interval at which the state of the compute service hosting an active OpenStackMachine is checked (e.g. 1m). Requires admin credentials. If unspecified, the check is disabled.")
+
+	fs.DurationVar(&instanceCheckInterval, "instance-check-interval", 0,
+		"Interval at which OpenStackMachines check that their instance still exists (e.g. 1m). A machine whose instance was deleted outside of Kubernetes is marked as failed. If unspecified, the check is disabled.")
+
+	fs.DurationVar(&loadBalancerMetricsInterval, "load-balancer-metrics-interval", 0,
+		"Interval at which the listener statistics and the member status of managed API server load balancers are exported as metrics (e.g. 1m). If unspecified, the metrics are disabled.")
+
+	fs.BoolVar(&enableInstanceResize, "enable-instance-resize", false,
+		"Enable resizing the instances of OpenStackMachines with resizeOnFlavorChange to a changed flavor instead of requiring the machine to be replaced.")
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 
@@ -194,17 +210,21 @@ func setupChecks(mgr ctrl.Manager) {
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&controllers.OpenStackClusterReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackcluster-controller"),
-		WatchFilterValue: watchFilterValue,
+		Client:                      mgr.GetClient(),
+		Recorder:                    mgr.GetEventRecorderFor("openstackcluster-controller"),
+		WatchFilterValue:            watchFilterValue,
+		LoadBalancerMetricsInterval: loadBalancerMetricsInterval,
 	}).SetupWithManager(ctx, mgr, concurrency(openStackClusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenStackCluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.OpenStackMachineReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackmachine-controller"),
-		WatchFilterValue: watchFilterValue,
+		Client:                   mgr.GetClient(),
+		Recorder:                 mgr.GetEventRecorderFor("openstackmachine-controller"),
+		WatchFilterValue:         watchFilterValue,
+		ComputeHostCheckInterval: computeHostCheckInterval,
+		InstanceCheckInterval:    instanceCheckInterval,
+		EnableInstanceResize:     enableInstanceResize,
 	}).SetupWithManager(ctx, mgr, concurrency(openStackMachineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachine")
 		os.Exit(1)
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/common/extensions"
+	netext "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+)
+
+const (
+	extensionTrunk          = "trunk"
+	extensionDNSIntegration = "dns-integration"
+)
+
+// check collects the missing capabilities of a cloud. The Neutron extensions
+// are only fetched once per check.
+type check struct {
+	s *Service
+
+	extensions map[string]bool
+	missing    []string
+}
+
+// MissingClusterCapabilities returns the capabilities which are required by the
+// spec of the cluster, including its bastion, but which the cloud doesn't provide.
+func (s *Service) MissingClusterCapabilities(openStackCluster *infrav1.OpenStackCluster) ([]string, error) {
+	c := &check{s: s}
+
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer {
+		if err := c.requireService("load balancer service (Octavia)", openstack.NewLoadBalancerV2); err != nil {
+			return nil, err
+		}
+	}
+	if openStackCluster.Spec.NodeDNSRecords != nil {
+		if err := c.requireService("DNS service (Designate)", openstack.NewDNSV2); err != nil {
+			return nil, err
+		}
+	}
+	if openStackCluster.Spec.DNSDomain != "" {
+		if err := c.requireExtension(extensionDNSIntegration); err != nil {
+			return nil, err
+		}
+	}
+	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
+		if err := c.checkMachineSpec(&openStackCluster.Spec.Bastion.Instance); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.missing, nil
+}
+
+// MissingMachineCapabilities returns the capabilities which are required by the
+// spec of the machine, but which the cloud doesn't provide.
+func (s *Service) MissingMachineCapabilities(openStackMachineSpec *infrav1.OpenStackMachineSpec) ([]string, error) {
+	c := &check{s: s}
+	if err := c.checkMachineSpec(openStackMachineSpec); err != nil {
+		return nil, err
+	}
+	return c.missing, nil
+}
+
+func (c *check) checkMachineSpec(openStackMachineSpec *infrav1.OpenStackMachineSpec) error {
+	if openStackMachineSpec.Trunk {
+		if err := c.requireExtension(extensionTrunk); err != nil {
+			return err
+		}
+	}
+	if usesPortDNS(openStackMachineSpec) {
+		if err := c.requireExtension(extensionDNSIntegration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usesPortDNS reports whether the ports of the machine get a dns_name or a
+// dns_domain.
+func usesPortDNS(openStackMachineSpec *infrav1.OpenStackMachineSpec) bool {
+	if openStackMachineSpec.Hostname != "" {
+		return true
+	}
+	for _, port := range openStackMachineSpec.Ports {
+		if port.DNSName != "" || port.DNSDomain != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *check) addMissing(capability string) {
+	for _, m := range c.missing {
+		if m == capability {
+			return
+		}
+	}
+	c.missing = append(c.missing, capability)
+}
+
+// requireService checks that the service catalog contains an endpoint of the service.
+func (c *check) requireService(name string, newClient func(*gophercloud.ProviderClient, gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error)) error {
+	_, err := newClient(c.s.provider, gophercloud.EndpointOpts{Region: c.s.regionName})
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *gophercloud.ErrEndpointNotFound, gophercloud.ErrEndpointNotFound:
+		c.addMissing(name)
+		return nil
+	}
+	return fmt.Errorf("error looking up the endpoint of the %s: %v", name, err)
+}
+
+// requireExtension checks that the Neutron extension with the given alias is enabled.
+func (c *check) requireExtension(alias string) error {
+	if c.extensions == nil {
+		allPages, err := netext.List(c.s.networkClient).AllPages()
+		if err != nil {
+			return fmt.Errorf("error listing networking extensions: %v", err)
+		}
+		allExts, err := extensions.ExtractExtensions(allPages)
+		if err != nil {
+			return fmt.Errorf("error extracting networking extensions: %v", err)
+		}
+		c.extensions = make(map[string]bool, len(allExts))
+		for _, ext := range allExts {
+			c.extensions[ext.Alias] = true
+		}
+	}
+
+	if !c.extensions[alias] {
+		c.addMissing(fmt.Sprintf("networking extension %s", alias))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+)
+
+// Service checks whether the OpenStack cloud provides the services and
+// extensions which are required by a spec.
+type Service struct {
+	provider      *gophercloud.ProviderClient
+	regionName    string
+	networkClient *gophercloud.ServiceClient
+	logger        logr.Logger
+}
+
+// NewService returns an instance of the capabilities service.
+func NewService(client *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, logger logr.Logger) (*Service, error) {
+	networkingClient, err := openstack.NewNetworkV2(client, gophercloud.EndpointOpts{
+		Region: clientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create networking service client: %v", err)
+	}
+
+	return &Service{
+		provider:      client,
+		regionName:    clientOpts.RegionName,
+		networkClient: networkingClient,
+		logger:        logger,
+	}, nil
+}
@@ -18,8 +18,13 @@ package compute
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 )
 
 func (s *Service) GetAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
@@ -34,3 +39,41 @@ func (s *Service) GetAvailabilityZones() ([]availabilityzones.AvailabilityZone,
 
 	return availabilityZoneList, nil
 }
+
+// getInstanceFailureDomain returns the availability zone the instance of the machine is created in.
+// This is the failure domain of the machine, unless its availability zone is unavailable and the
+// fallback policy of the cluster allows to use another available failure domain of the cluster.
+func (s *Service) getInstanceFailureDomain(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine) (string, error) {
+	failureDomain := *machine.Spec.FailureDomain
+	if openStackCluster.Spec.FailureDomainFallbackPolicy != infrav1.FailureDomainFallbackAnyAvailable {
+		return failureDomain, nil
+	}
+
+	availabilityZones, err := s.GetAvailabilityZones()
+	if err != nil {
+		return "", err
+	}
+	available := map[string]bool{}
+	for _, az := range availabilityZones {
+		available[az.ZoneName] = az.ZoneState.Available
+	}
+	if available[failureDomain] {
+		return failureDomain, nil
+	}
+
+	candidates := []string{}
+	for name, fd := range openStackCluster.Status.FailureDomains {
+		if util.IsControlPlaneMachine(machine) && !fd.ControlPlane {
+			continue
+		}
+		if available[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("availability zone %q is unavailable and there is no other available failure domain", failureDomain)
+	}
+	sort.Strings(candidates)
+	s.logger.Info("Availability zone is unavailable, using another failure domain", "failureDomain", failureDomain, "fallback", candidates[0])
+	return candidates[0], nil
+}
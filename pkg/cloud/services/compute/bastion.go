@@ -34,12 +34,12 @@ func (s *Service) DeleteBastion(openStackCluster *infrav1.OpenStackCluster, serv
 	if instance == nil {
 		return nil
 	}
-	if err = deleteInstance(s, instance.ID); err != nil {
+	if err = deleteInstance(s, instance.ID, nil, openStackCluster.Spec.Timeouts); err != nil {
 		record.Warnf(openStackCluster, "FailedDeleteServer", "Failed to delete server %s with id %s: %v", instance.Name, instance.ID, err)
 		return err
 	}
 
-	err = util.PollImmediate(RetryIntervalInstanceStatus, TimeoutInstanceDelete, func() (bool, error) {
+	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceDeleteTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
 		_, err = s.GetInstance(instance.ID)
 		if err != nil {
 			if capoerrors.IsNotFound(err) {
@@ -58,18 +58,42 @@ func (s *Service) DeleteBastion(openStackCluster *infrav1.OpenStackCluster, serv
 	return nil
 }
 
+// CreateBastion creates the bastion of the cluster. It supports the options of
+// the instance of a machine which don't depend on a Machine, e.g. no failure
+// domain, user data nor trunk.
 func (s *Service) CreateBastion(openStackCluster *infrav1.OpenStackCluster, clusterName string) (*infrav1.Instance, error) {
 	name := fmt.Sprintf("%s-bastion", clusterName)
+	spec := &openStackCluster.Spec.Bastion.Instance
 	input := &infrav1.Instance{
 		Name:          name,
-		Flavor:        openStackCluster.Spec.Bastion.Instance.Flavor,
-		SSHKeyName:    openStackCluster.Spec.Bastion.Instance.SSHKeyName,
-		Image:         openStackCluster.Spec.Bastion.Instance.Image,
+		Flavor:        spec.Flavor,
+		FlavorID:      spec.FlavorID,
+		SSHKeyName:    sshKeyName(openStackCluster, spec.SSHKeyName),
+		Image:         spec.Image,
+		ImageUUID:     spec.ImageUUID,
+		ImageFilter:   spec.ImageFilter,
 		FailureDomain: openStackCluster.Spec.Bastion.AvailabilityZone,
-		RootVolume:    openStackCluster.Spec.Bastion.Instance.RootVolume,
+		RootVolume:    spec.RootVolume,
+		ConfigDrive:   configDrive(openStackCluster, spec.ConfigDrive),
+		Subnet:        spec.Subnet,
+		ServerGroupID: spec.ServerGroupID,
 	}
 
-	securityGroups, err := getSecurityGroups(s, openStackCluster.Spec.Bastion.Instance.SecurityGroups)
+	// The bastion gets the tags and the metadata of its instance spec like the
+	// servers of machines, e.g. for compliance scanners.
+	bastionMachine := &infrav1.OpenStackMachine{Spec: *spec}
+	input.Tags = instanceTags(openStackCluster, bastionMachine)
+	input.Metadata = instanceMetadata(bastionMachine)
+
+	if spec.ServerGroupName != "" {
+		serverGroupID, err := getServerGroupID(s, spec.ServerGroupName)
+		if err != nil {
+			return nil, err
+		}
+		input.ServerGroupID = serverGroupID
+	}
+
+	securityGroups, err := getSecurityGroups(s, spec.SecurityGroups)
 	if err != nil {
 		return nil, err
 	}
@@ -79,28 +103,29 @@ func (s *Service) CreateBastion(openStackCluster *infrav1.OpenStackCluster, clus
 	input.SecurityGroups = &securityGroups
 
 	var nets []infrav1.Network
-	if len(openStackCluster.Spec.Bastion.Instance.Networks) > 0 {
+	if len(spec.Ports) > 0 {
+		nets = portNetworks(openStackCluster, spec.Ports)
+	} else if len(spec.Networks) > 0 {
 		var err error
-		nets, err = getServerNetworks(s.networkClient, openStackCluster.Spec.Bastion.Instance.Networks)
+		nets, err = getServerNetworks(s.networkClient, spec.Networks)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		nets = []infrav1.Network{{
-			ID: openStackCluster.Status.Network.ID,
-			Subnet: &infrav1.Subnet{
-				ID: openStackCluster.Status.Network.Subnet.ID,
-			},
-		}}
+		nets = []infrav1.Network{clusterNetwork(openStackCluster)}
 	}
 	input.Networks = &nets
 
-	out, err := createInstance(s, clusterName, input)
+	out, err := createInstance(s, clusterName, input, nil, nil, "", nil, spec.AdditionalBlockDevices, openStackCluster.Spec.Timeouts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateServer", "Failed to create server %s: %v", name, err)
 		return nil, err
 	}
 
 	record.Eventf(openStackCluster, "SuccessfulCreateServer", "Created server %s with id %s", name, out.ID)
+
+	if openStackCluster.Spec.LockInstances {
+		lockInstance(s, openStackCluster, out)
+	}
 	return out, nil
 }
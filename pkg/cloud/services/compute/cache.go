@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// lookupCacheSize is the maximum number of cached lookups.
+	lookupCacheSize = 1024
+	// lookupCacheTTL is the time after which a cached lookup is performed again,
+	// e.g. to notice a renamed image.
+	lookupCacheTTL = 5 * time.Minute
+)
+
+// lookupCache holds the IDs of images and flavors looked up by name. It is
+// shared by all instances of the compute service, as a new one is created
+// for each reconcile.
+var lookupCache = cache.NewLRUExpireCache(lookupCacheSize)
+
+// cachedLookup returns the cached ID of the named resource, or performs the
+// lookup and caches its result. Failed lookups are not cached. The endpoint
+// and the project are part of the key, as the same name may refer to different
+// resources in different clouds and projects.
+func (s *Service) cachedLookup(kind, endpoint, name string, lookup func() (string, error)) (string, error) {
+	key := fmt.Sprintf("%s/%s/%s/%s", kind, endpoint, s.projectID, name)
+	if id, ok := lookupCache.Get(key); ok {
+		return id.(string), nil
+	}
+
+	id, err := lookup()
+	if err != nil {
+		return "", err
+	}
+	lookupCache.Add(key, id, lookupCacheTTL)
+	return id, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/services"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+)
+
+const (
+	computeServiceBinary    = "nova-compute"
+	computeServiceStateDown = "down"
+)
+
+// IsComputeHostDown returns the host of the server and whether the
+// nova-compute service on that host is reported down. Both the host of a
+// server and the compute services are only exposed to administrators by the
+// default Nova policy.
+func (s *Service) IsComputeHostDown(serverID string) (string, bool, error) {
+	var server struct {
+		servers.Server
+		extendedserverattributes.ServerAttributesExt
+	}
+	if err := servers.Get(s.computeClient, serverID).ExtractInto(&server); err != nil {
+		return "", false, fmt.Errorf("get server %q: %v", serverID, err)
+	}
+	if server.Host == "" {
+		return "", false, fmt.Errorf("host of server %q is not exposed, admin credentials are required", serverID)
+	}
+
+	allPages, err := services.List(s.computeClient, services.ListOpts{
+		Binary: computeServiceBinary,
+		Host:   server.Host,
+	}).AllPages()
+	if err != nil {
+		return "", false, fmt.Errorf("list compute services on host %q: %v", server.Host, err)
+	}
+	computeServices, err := services.ExtractServices(allPages)
+	if err != nil {
+		return "", false, fmt.Errorf("extract compute services on host %q: %v", server.Host, err)
+	}
+	for _, service := range computeServices {
+		if service.State == computeServiceStateDown {
+			return server.Host, true, nil
+		}
+	}
+	return server.Host, false, nil
+}
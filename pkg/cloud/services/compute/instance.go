@@ -21,26 +21,45 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/common/extensions"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/serverusage"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/startstop"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	netext "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/qos/policies"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
-	"github.com/gophercloud/utils/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/pagination"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/util"
@@ -48,7 +67,9 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/diff"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/microversion"
 )
 
 const (
@@ -62,32 +83,68 @@ const (
 	RetryIntervalPortDelete = 5 * time.Second
 
 	TimeoutInstanceDelete = 5 * time.Minute
+	TimeoutInstanceStop   = 5 * time.Minute
+
+	TimeoutVolumeCreate       = 5 * time.Minute
+	TimeoutVolumeDetach       = 5 * time.Minute
+	RetryIntervalVolumeStatus = 5 * time.Second
 )
 
+// maxConcurrentInterfaceDeletes limits the interfaces of an instance which are
+// deleted at the same time.
+const maxConcurrentInterfaceDeletes = 5
+
+// computeMicroversionVolumeType is the minimum compute API microversion which
+// accepts the volume type of a block device.
+const computeMicroversionVolumeType = "2.67"
+
 // InstanceCreate creates a compute instance.
 func (s *Service) InstanceCreate(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, clusterName string, userData string) (instance *infrav1.Instance, err error) {
 	if openStackMachine == nil {
 		return nil, fmt.Errorf("create Options need be specified to create instace")
 	}
 
-	if machine.Spec.FailureDomain == nil {
-		return nil, fmt.Errorf("failure domain not set")
+	// Without a failure domain, Nova schedules the instance to its default availability zone.
+	var failureDomain string
+	if machine.Spec.FailureDomain != nil {
+		failureDomain, err = s.getInstanceFailureDomain(openStackCluster, machine)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	input := &infrav1.Instance{
-		Name:          openStackMachine.Name,
+		Name:          InstanceName(openStackMachine),
 		Image:         openStackMachine.Spec.Image,
+		ImageUUID:     openStackMachine.Spec.ImageUUID,
+		ImageFilter:   openStackMachine.Spec.ImageFilter,
 		Flavor:        openStackMachine.Spec.Flavor,
-		SSHKeyName:    openStackMachine.Spec.SSHKeyName,
+		FlavorID:      openStackMachine.Spec.FlavorID,
+		SSHKeyName:    sshKeyName(openStackCluster, openStackMachine.Spec.SSHKeyName),
 		UserData:      userData,
-		Metadata:      openStackMachine.Spec.ServerMetadata,
-		ConfigDrive:   openStackMachine.Spec.ConfigDrive,
-		FailureDomain: *machine.Spec.FailureDomain,
+		ConfigDrive:   configDrive(openStackCluster, openStackMachine.Spec.ConfigDrive),
+		FailureDomain: failureDomain,
 		RootVolume:    openStackMachine.Spec.RootVolume,
 		Subnet:        openStackMachine.Spec.Subnet,
+		ServerGroupID: openStackMachine.Spec.ServerGroupID,
+	}
+
+	if openStackMachine.Spec.ServerGroupName != "" {
+		input.ServerGroupID, err = getServerGroupID(s, openStackMachine.Spec.ServerGroupName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Fall back to the managed server group of the machine.
+	if input.ServerGroupID == "" {
+		input.ServerGroupID = openStackMachine.Status.ServerGroupID
 	}
 
-	if openStackMachine.Spec.Trunk {
+	if len(openStackMachine.Spec.Subports) > 0 && !firstPortUsesTrunk(openStackMachine) {
+		return nil, fmt.Errorf("subports require a trunk on the first port of the machine")
+	}
+	if openStackMachine.Spec.Trunk || portsUseTrunk(openStackMachine.Spec.Ports) {
 		trunkSupport, err := getTrunkSupport(s)
 		if err != nil {
 			return nil, fmt.Errorf("there was an issue verifying whether trunk support is available, please disable it: %v", err)
@@ -95,7 +152,7 @@ func (s *Service) InstanceCreate(openStackCluster *infrav1.OpenStackCluster, mac
 		if !trunkSupport {
 			return nil, fmt.Errorf("there is no trunk support. Please disable it")
 		}
-		input.Trunk = trunkSupport
+		input.Trunk = openStackMachine.Spec.Trunk
 	}
 
 	machineTags := []string{}
@@ -106,69 +163,237 @@ func (s *Service) InstanceCreate(openStackCluster *infrav1.OpenStackCluster, mac
 	// Append cluster scope tags
 	machineTags = append(machineTags, openStackCluster.Spec.Tags...)
 
+	// The server and the trunk get the tags of their service in addition.
 	// tags need to be unique or the "apply tags" call will fail.
-	machineTags = deduplicate(machineTags)
+	input.Tags = instanceTags(openStackCluster, openStackMachine)
+	trunkTags := deduplicate(append(append([]string{}, machineTags...), openStackCluster.Spec.NetworkTags...))
+	// The ports are tagged with the machine, so that orphaned ports can be
+	// garbage collected.
+	portTags := []string{machinePortTag(openStackMachine)}
+	dnsName := instanceDNSName(openStackMachine)
 
-	input.Tags = machineTags
+	input.Metadata = instanceMetadata(openStackMachine)
 
 	// Get security groups
-	securityGroups, err := getSecurityGroups(s, openStackMachine.Spec.SecurityGroups)
+	securityGroups, err := machineSecurityGroups(s, openStackCluster, machine, openStackMachine)
 	if err != nil {
 		return nil, err
 	}
-	if openStackCluster.Spec.ManagedSecurityGroups {
-		if util.IsControlPlaneMachine(machine) {
-			securityGroups = append(securityGroups, openStackCluster.Status.ControlPlaneSecurityGroup.ID)
-		} else {
-			securityGroups = append(securityGroups, openStackCluster.Status.WorkerSecurityGroup.ID)
-		}
-	}
 	input.SecurityGroups = &securityGroups
 
 	var nets []infrav1.Network
-	if len(openStackMachine.Spec.Networks) > 0 {
+	if len(openStackMachine.Spec.Ports) > 0 {
+		// Ports take precedence over networks.
+		nets = portNetworks(openStackCluster, openStackMachine.Spec.Ports)
+	} else if len(openStackMachine.Spec.Networks) > 0 {
 		var err error
 		nets, err = getServerNetworks(s.networkClient, openStackMachine.Spec.Networks)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		nets = []infrav1.Network{{
-			ID: openStackCluster.Status.Network.ID,
-			Subnet: &infrav1.Subnet{
-				ID: openStackCluster.Status.Network.Subnet.ID,
-			},
-		}}
+		nets = []infrav1.Network{clusterNetwork(openStackCluster)}
+	}
+	additionalNets, err := getAdditionalNetworks(s.networkClient, openStackCluster.Spec.AdditionalNetworks, machine)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the address of the machine on its primary network if additional
+	// networks, e.g. a control plane only etcd network, are attached.
+	if len(additionalNets) > 0 && input.Subnet == "" && nets[0].Subnet != nil {
+		input.Subnet = nets[0].Subnet.ID
+	}
+	nets = append(nets, additionalNets...)
+	if util.IsControlPlaneMachine(machine) {
+		addAPIServerVIP(openStackCluster, nets)
 	}
 	input.Networks = &nets
 
-	out, err := createInstance(s, clusterName, input)
+	out, err := createInstance(s, clusterName, input, trunkTags, portTags, dnsName, openStackMachine.Spec.Subports, openStackMachine.Spec.AdditionalBlockDevices, openStackCluster.Spec.Timeouts)
 	if err != nil {
 		record.Warnf(openStackMachine, "FailedCreateServer", "Failed to create server %s: %v", input.Name, err)
 		return nil, err
 	}
 	record.Eventf(openStackMachine, "SuccessfulCreateServer", "Created server %s with id %s", out.Name, out.ID)
+
+	if openStackCluster.Spec.LockInstances {
+		lockInstance(s, openStackMachine, out)
+	}
 	return out, nil
 }
 
-func createInstance(is *Service, clusterName string, i *infrav1.Instance) (*infrav1.Instance, error) {
-	// Get image ID
-	imageID, err := getImageID(is, i.Image)
+// configDrive returns whether a config drive is attached to a server, which
+// defaults to the ConfigDrive of the cluster.
+func configDrive(openStackCluster *infrav1.OpenStackCluster, machineConfigDrive *bool) *bool {
+	if machineConfigDrive != nil {
+		return machineConfigDrive
+	}
+	return openStackCluster.Spec.ConfigDrive
+}
+
+// InstanceName returns the name of the server of the machine, which is its
+// Hostname template filled in, or the name of the machine.
+func InstanceName(openStackMachine *infrav1.OpenStackMachine) string {
+	if openStackMachine.Spec.Hostname == "" {
+		return openStackMachine.Name
+	}
+	return strings.NewReplacer(
+		"{machine}", openStackMachine.Name,
+		"{cluster}", openStackMachine.Labels[clusterv1.ClusterLabelName],
+		"{namespace}", openStackMachine.Namespace,
+	).Replace(openStackMachine.Spec.Hostname)
+}
+
+// instanceDNSName returns the dns_name of the primary port of the server of
+// the machine, which is the first label of its name. Without a Hostname
+// template, no dns_name is set.
+func instanceDNSName(openStackMachine *infrav1.OpenStackMachine) string {
+	if openStackMachine.Spec.Hostname == "" {
+		return ""
+	}
+	return strings.SplitN(InstanceName(openStackMachine), ".", 2)[0]
+}
+
+// instanceMetadata returns the server metadata of the instance of the machine.
+func instanceMetadata(openStackMachine *infrav1.OpenStackMachine) map[string]string {
+	if !openStackMachine.Spec.InstanceHA {
+		return openStackMachine.Spec.ServerMetadata
+	}
+	metadata := map[string]string{}
+	for k, v := range openStackMachine.Spec.ServerMetadata {
+		metadata[k] = v
+	}
+	metadata[instanceHAMetadataKey] = instanceHAMetadataValue
+	return metadata
+}
+
+// LogInstanceDiff logs the differences between the instance described by the
+// machine and the observed instance at a high verbosity.
+func (s *Service) LogInstanceDiff(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) {
+	desired := infrav1.Instance{
+		Name:       InstanceName(openStackMachine),
+		SSHKeyName: sshKeyName(openStackCluster, openStackMachine.Spec.SSHKeyName),
+		State:      infrav1.InstanceStateActive,
+	}
+	if metadata := instanceMetadata(openStackMachine); len(metadata) > 0 {
+		desired.Metadata = metadata
+	}
+	observed := infrav1.Instance{
+		Name:       instance.Name,
+		SSHKeyName: instance.SSHKeyName,
+		Metadata:   instance.Metadata,
+		State:      instance.State,
+	}
+	diff.Log(s.logger, "Instance differs from desired state", desired, observed, "instance-id", instance.ID)
+}
+
+// lockInstance locks the instance against deletion outside of Cluster API. The
+// instance works without the lock, so a failure is only reported as an event.
+func lockInstance(is *Service, obj runtime.Object, instance *infrav1.Instance) {
+	if err := lockunlock.Lock(is.computeClient, instance.ID).ExtractErr(); err != nil {
+		is.logger.Info("Failed to lock server", "id", instance.ID, "error", err.Error())
+		record.Warnf(obj, "FailedLockServer", "Failed to lock server %s with id %s: %v", instance.Name, instance.ID, err)
+		return
+	}
+	record.Eventf(obj, "SuccessfulLockServer", "Locked server %s with id %s", instance.Name, instance.ID)
+}
+
+func createInstance(is *Service, clusterName string, i *infrav1.Instance, trunkTags, portTags []string, dnsName string, subports []infrav1.Subport, additionalBlockDevices []infrav1.AdditionalBlockDevice, timeouts *infrav1.Timeouts) (*infrav1.Instance, error) {
+	// Get image ID, unless the instance boots from an existing volume.
+	var imageID string
+	var err error
+	if !bootsFromExistingVolume(i.RootVolume) {
+		imageID, err = resolveImageID(is, i.ImageUUID, i.ImageFilter, i.Image)
+		if err != nil {
+			return nil, fmt.Errorf("create new server err: %v", err)
+		}
+	}
+
+	// Pin the compute API microversion the features of the server require,
+	// before any resource of the server is created.
+	var features []computeFeature
+	serverTags := i.Tags
+	serverMetadata := i.Metadata
+	if len(i.Tags) > 0 {
+		maximum, err := is.maxComputeMicroversion()
+		if err != nil {
+			return nil, fmt.Errorf("create new server err: %v", err)
+		}
+		tagsSupported, err := microversion.AtLeast(maximum, computeMicroversionTagsOnCreate)
+		if err != nil {
+			return nil, fmt.Errorf("create new server err: %v", err)
+		}
+		if tagsSupported {
+			features = append(features, computeFeature{name: "server tags", microversion: computeMicroversionTagsOnCreate})
+		} else {
+			// Fall back to tags in the metadata of the server.
+			serverTags = nil
+			serverMetadata = map[string]string{}
+			for k, v := range i.Metadata {
+				serverMetadata[k] = v
+			}
+			for k, v := range metadataTags(i.Tags) {
+				serverMetadata[k] = v
+			}
+		}
+	}
+	if rootVolumeType(i.RootVolume, i.FailureDomain) != "" {
+		// The volume type of a block device can only be passed to Nova with
+		// microversion 2.67 or later.
+		features = append(features, computeFeature{name: "volume types of block devices", microversion: computeMicroversionVolumeType})
+	}
+	computeClient, err := is.computeClientFor(features...)
 	if err != nil {
 		return nil, fmt.Errorf("create new server err: %v", err)
 	}
 
-	accessIPv4 := ""
+	if err := is.validateVolumes(i, additionalBlockDevices); err != nil {
+		return nil, fmt.Errorf("create new server err: %v", err)
+	}
+
 	networkList := i.Networks
+	primaryPort, err := primaryPortIndex(*networkList)
+	if err != nil {
+		return nil, fmt.Errorf("create new server err: %v", err)
+	}
 	portsList := []servers.Network{}
-	for _, network := range *networkList {
+	// ownedPorts are the ports which are deleted if the instance can not be
+	// created, i.e. all ports but the existing ports of the machine.
+	ownedPorts := []servers.Network{}
+	serverPorts := []ports.Port{}
+	for index, network := range *networkList {
 		network := network
+		var port ports.Port
+		if network.PortOpts != nil && network.PortOpts.PortID != "" {
+			existingPort, err := ports.Get(is.networkClient, network.PortOpts.PortID).Extract()
+			if err != nil {
+				return nil, fmt.Errorf("get existing port %s for server: %v", network.PortOpts.PortID, err)
+			}
+			port = *existingPort
+			serverPorts = append(serverPorts, port)
+			portsList = append(portsList, servers.Network{
+				Port: port.ID,
+			})
+			continue
+		}
 		if network.ID == "" {
 			return nil, fmt.Errorf("no network was found or provided. Please check your machine configuration and try again")
 		}
+		name := i.Name
+		trunk := i.Trunk
+		if network.PortOpts != nil {
+			name = portName(i.Name, network.PortOpts, index)
+			if network.PortOpts.Trunk != nil {
+				trunk = *network.PortOpts.Trunk
+			}
+		}
+		// The ports of networks without port options share the name of the
+		// instance, so the index tells them apart.
+		indexTag := portIndexTag(index)
 		allPages, err := ports.List(is.networkClient, ports.ListOpts{
-			Name:      i.Name,
+			Name:      name,
 			NetworkID: network.ID,
+			Tags:      indexTag,
 		}).AllPages()
 		if err != nil {
 			return nil, fmt.Errorf("searching for existing port for server: %v", err)
@@ -177,28 +402,39 @@ func createInstance(is *Service, clusterName string, i *infrav1.Instance) (*infr
 		if err != nil {
 			return nil, fmt.Errorf("searching for existing port for server err: %v", err)
 		}
-		var port ports.Port
 		if len(portList) == 0 {
+			// Ports created before the index tag was introduced only carry
+			// the name of the instance.
+			portList, err = getUnindexedPorts(is, name, network.ID, indexTag)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(portList) == 0 {
+			// The dns_name must be unique in the network and the DNS domain,
+			// so only the primary port is named after the server.
+			portDNSName := ""
+			if index == primaryPort {
+				portDNSName = dnsName
+			}
 			// create server port
-			port, err = createPort(is, clusterName, i.Name, &network, i.SecurityGroups)
+			port, err = createPort(is, clusterName, name, &network, i.SecurityGroups, append(append([]string{}, portTags...), indexTag), portDNSName)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create port err: %v", err)
 			}
 		} else {
 			port = portList[0]
 		}
+		ownedPorts = append(ownedPorts, servers.Network{
+			Port: port.ID,
+		})
 
-		for _, fip := range port.FixedIPs {
-			if fip.SubnetID == i.Subnet {
-				accessIPv4 = fip.IPAddress
-			}
-		}
-
+		serverPorts = append(serverPorts, port)
 		portsList = append(portsList, servers.Network{
 			Port: port.ID,
 		})
 
-		if i.Trunk {
+		if trunk {
 			allPages, err := trunks.List(is.networkClient, trunks.ListOpts{
 				Name:   i.Name,
 				PortID: port.ID,
@@ -227,24 +463,64 @@ func createInstance(is *Service, clusterName string, i *infrav1.Instance) (*infr
 			}
 
 			_, err = attributestags.ReplaceAll(is.networkClient, "trunks", trunk.ID, attributestags.ReplaceAllOpts{
-				Tags: i.Tags,
+				Tags: trunkTags,
 			}).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("tagging trunk for server err: %v", err)
 			}
+
+			if index == 0 && len(subports) > 0 {
+				if err := reconcileSubports(is, clusterName, i.Name, trunk, subports, i.SecurityGroups, portTags); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 
-	if i.Subnet != "" && accessIPv4 == "" {
-		if errd := deletePorts(is, portsList); errd != nil {
+	accessIPv4, accessIPv6, onSubnet := accessIPs(serverPorts, primaryPort, i.Subnet)
+	if i.Subnet != "" && !onSubnet {
+		if errd := deletePorts(is, ownedPorts); errd != nil {
 			return nil, fmt.Errorf("no ports with fixed IPs found on Subnet %q: error cleaning up ports: %v", i.Subnet, errd)
 		}
 		return nil, fmt.Errorf("no ports with fixed IPs found on Subnet %q", i.Subnet)
 	}
 
-	flavorID, err := flavors.IDFromName(is.computeClient, i.Flavor)
+	userData, err := withNetworkConfig(is, i.UserData, serverPorts)
+	if err != nil {
+		if errd := deletePorts(is, ownedPorts); errd != nil {
+			return nil, fmt.Errorf("error creating network config: %v: error cleaning up ports: %v", err, errd)
+		}
+		return nil, fmt.Errorf("error creating network config: %v", err)
+	}
+
+	compressedUserData, err := compressUserData(userData)
 	if err != nil {
-		return nil, fmt.Errorf("error getting flavor id from flavor name %s: %v", i.Flavor, err)
+		if errd := deletePorts(is, ownedPorts); errd != nil {
+			return nil, fmt.Errorf("error preparing user data: %v: error cleaning up ports: %v", err, errd)
+		}
+		return nil, fmt.Errorf("error preparing user data: %v", err)
+	}
+
+	configDrive := i.ConfigDrive
+	if configDrive == nil {
+		static, err := hasStaticAddresses(is, serverPorts)
+		if err != nil {
+			if errd := deletePorts(is, ownedPorts); errd != nil {
+				return nil, fmt.Errorf("error checking subnets: %v: error cleaning up ports: %v", err, errd)
+			}
+			return nil, fmt.Errorf("error checking subnets: %v", err)
+		}
+		if static {
+			configDrive = pointer.BoolPtr(true)
+		}
+	}
+
+	flavorID := i.FlavorID
+	if flavorID == "" {
+		flavorID, err = getFlavorID(is, i.Flavor)
+		if err != nil {
+			return nil, fmt.Errorf("error getting flavor id from flavor name %s: %v", i.Flavor, err)
+		}
 	}
 
 	var serverCreateOpts servers.CreateOptsBuilder = servers.CreateOpts{
@@ -253,32 +529,96 @@ func createInstance(is *Service, clusterName string, i *infrav1.Instance) (*infr
 		FlavorRef:        flavorID,
 		AvailabilityZone: i.FailureDomain,
 		Networks:         portsList,
-		UserData:         []byte(i.UserData),
+		UserData:         []byte(compressedUserData),
 		SecurityGroups:   *i.SecurityGroups,
-		Tags:             i.Tags,
-		Metadata:         i.Metadata,
-		ConfigDrive:      i.ConfigDrive,
+		Tags:             serverTags,
+		Metadata:         serverMetadata,
+		ConfigDrive:      configDrive,
 		AccessIPv4:       accessIPv4,
+		AccessIPv6:       accessIPv6,
+	}
+
+	volumeType := rootVolumeType(i.RootVolume, i.FailureDomain)
+	// Nova creates the root volume in its own availability zone, so a root
+	// volume in another availability zone is created with Cinder beforehand.
+	createRootVolume := i.RootVolume != nil && i.RootVolume.Size != 0 && i.RootVolume.AvailabilityZone != "" && !bootsFromExistingVolume(i.RootVolume)
+
+	var volumeClient *gophercloud.ServiceClient
+	var rootVolumeID string
+	var createdVolumeIDs []string
+	if createRootVolume || len(additionalBlockDevices) > 0 {
+		volumeClient, err = is.getVolumeClient()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if createRootVolume {
+		createOpts := volumes.CreateOpts{
+			Name:             rootVolumeName(i.Name),
+			Size:             i.RootVolume.Size,
+			VolumeType:       volumeType,
+			AvailabilityZone: i.RootVolume.AvailabilityZone,
+		}
+		if i.RootVolume.SourceType == string(bootfromvolume.SourceImage) {
+			createOpts.ImageID = i.RootVolume.SourceUUID
+		}
+		volume, err := getOrCreateVolume(volumeClient, createOpts)
+		if err != nil {
+			if errd := deletePorts(is, ownedPorts); errd != nil {
+				return nil, fmt.Errorf("error creating root volume: %v: error cleaning up ports: %v", err, errd)
+			}
+			if errd := deleteVolumes(volumeClient, createdVolumeIDs); errd != nil {
+				return nil, fmt.Errorf("error creating root volume: %v: error cleaning up volumes: %v", err, errd)
+			}
+			return nil, fmt.Errorf("error creating root volume: %v", err)
+		}
+		rootVolumeID = volume.ID
+		createdVolumeIDs = append(createdVolumeIDs, volume.ID)
+		// The volume type has already been applied by Cinder.
+		volumeType = ""
+	}
+	var additionalVolumeIDs []string
+	for idx := range additionalBlockDevices {
+		blockDevice := &additionalBlockDevices[idx]
+		volume, err := getOrCreateVolume(volumeClient, volumes.CreateOpts{
+			Name:             additionalVolumeName(i.Name, blockDevice),
+			Size:             blockDevice.Size,
+			VolumeType:       blockDevice.VolumeType,
+			AvailabilityZone: blockDevice.AvailabilityZone,
+			Metadata:         blockDevice.Metadata,
+		})
+		if err != nil {
+			if errd := deletePorts(is, ownedPorts); errd != nil {
+				return nil, fmt.Errorf("error creating additional volume: %v: error cleaning up ports: %v", err, errd)
+			}
+			if errd := deleteVolumes(volumeClient, createdVolumeIDs); errd != nil {
+				return nil, fmt.Errorf("error creating additional volume: %v: error cleaning up volumes: %v", err, errd)
+			}
+			return nil, fmt.Errorf("error creating additional volume: %v", err)
+		}
+		additionalVolumeIDs = append(additionalVolumeIDs, volume.ID)
+		createdVolumeIDs = append(createdVolumeIDs, volume.ID)
 	}
 
-	serverCreateOpts = applyRootVolume(serverCreateOpts, i.RootVolume)
+	serverCreateOpts = applyBlockDevices(serverCreateOpts, imageID, i.RootVolume, rootVolumeID, volumeType, additionalBlockDevices, additionalVolumeIDs)
 
 	serverCreateOpts = applyServerGroupID(serverCreateOpts, i.ServerGroupID)
 
-	server, err := servers.Create(is.computeClient, keypairs.CreateOptsExt{
+	server, err := servers.Create(computeClient, keypairs.CreateOptsExt{
 		CreateOptsBuilder: serverCreateOpts,
 		KeyName:           i.SSHKeyName,
 	}).Extract()
 	if err != nil {
-		if errd := deletePorts(is, portsList); errd != nil {
+		if errd := deletePorts(is, ownedPorts); errd != nil {
 			return nil, fmt.Errorf("error recover creating Openstack instance: error cleaning up ports: %v", errd)
 		}
+		if errd := deleteVolumes(volumeClient, createdVolumeIDs); errd != nil {
+			return nil, fmt.Errorf("error recover creating Openstack instance: error cleaning up volumes: %v", errd)
+		}
 		return nil, fmt.Errorf("error creating Openstack instance: %v", err)
 	}
-	instanceCreateTimeout := getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", TimeoutInstanceCreate)
-	instanceCreateTimeout *= time.Minute
 	var instance *infrav1.Instance
-	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceCreateTimeout, func() (bool, error) {
+	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceCreateTimeout(timeouts), func() (bool, error) {
 		instance, err = is.GetInstance(server.ID)
 		if err != nil {
 			if capoerrors.IsRetryable(err) {
@@ -286,39 +626,130 @@ func createInstance(is *Service, clusterName string, i *infrav1.Instance) (*infr
 			}
 			return false, err
 		}
+		// Don't wait for the timeout if the server can't become active anymore.
+		if instance.State == infrav1.InstanceStateError {
+			if fault := is.InstanceFault(server.ID); fault != "" {
+				return false, fmt.Errorf("instance is in state %s: %s", instance.State, fault)
+			}
+			return false, fmt.Errorf("instance is in state %s", instance.State)
+		}
 		return instance.State == infrav1.InstanceStateActive, nil
 	})
 	if err != nil {
+		if failure := is.InstanceActionFailure(server.ID); failure != "" {
+			return nil, fmt.Errorf("error creating Openstack instance %s, %v: %s", server.ID, err, failure)
+		}
 		return nil, fmt.Errorf("error creating Openstack instance %s, %v", server.ID, err)
 	}
 	return instance, nil
 }
 
-func serverToInstance(v *servers.Server) (*infrav1.Instance, error) {
-	if v == nil {
+// extendedServer is a server with the extended attributes which are shown in
+// the status of the machine.
+type extendedServer struct {
+	servers.Server
+	availabilityzones.ServerAvailabilityZoneExt
+	extendedserverattributes.ServerAttributesExt
+	serverusage.UsageExt
+}
+
+func serverToInstance(s *extendedServer) (*infrav1.Instance, error) {
+	if s == nil {
 		return nil, nil
 	}
+	v := &s.Server
 	i := &infrav1.Instance{
-		ID:         v.ID,
-		Name:       v.Name,
-		SSHKeyName: v.KeyName,
-		State:      infrav1.InstanceState(v.Status),
+		ID:               v.ID,
+		Name:             v.Name,
+		SSHKeyName:       v.KeyName,
+		State:            infrav1.InstanceState(v.Status),
+		AvailabilityZone: s.AvailabilityZone,
+		HostID:           v.HostID,
+		Hypervisor:       s.HypervisorHostname,
+	}
+	if !s.LaunchedAt.IsZero() {
+		launchedAt := metav1.NewTime(s.LaunchedAt)
+		i.LaunchedAt = &launchedAt
+	}
+	if len(v.Metadata) > 0 {
+		i.Metadata = v.Metadata
+	}
+	// Servers which boot from a volume have no image.
+	if imageID, ok := v.Image["id"].(string); ok {
+		i.ImageUUID = imageID
+	}
+	// Since compute API microversion 2.47 only the name of the flavor is returned.
+	if flavorID, ok := v.Flavor["id"].(string); ok {
+		i.FlavorID = flavorID
 	}
 	addrMap, err := GetIPFromInstance(*v)
 	if err != nil {
 		return i, err
 	}
 	i.IP = addrMap["internal"]
+	i.IPv6 = addrMap["internalv6"]
+	// Instances of IPv6 only clusters have no IPv4 address.
+	if i.IP == "" {
+		i.IP = i.IPv6
+	}
 	if addrMap["floating"] != "" {
 		i.FloatingIP = addrMap["floating"]
 	}
+	i.Addresses, err = instanceAddresses(v)
+	if err != nil {
+		return i, err
+	}
 	return i, nil
 }
 
+// instanceAddresses returns all IPv4 and IPv6 addresses of the server, ordered
+// by the name of their network. Floating IPs are external addresses, all other
+// addresses are internal.
+func instanceAddresses(v *servers.Server) ([]corev1.NodeAddress, error) {
+	type networkInterface struct {
+		Address string `json:"addr"`
+		Type    string `json:"OS-EXT-IPS:type"`
+	}
+
+	networkNames := make([]string, 0, len(v.Addresses))
+	for name := range v.Addresses {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+
+	var addresses []corev1.NodeAddress
+	for _, name := range networkNames {
+		list, err := json.Marshal(v.Addresses[name])
+		if err != nil {
+			return nil, fmt.Errorf("extract addresses from instance err: %v", err)
+		}
+		var interfaces []networkInterface
+		if err := json.Unmarshal(list, &interfaces); err != nil {
+			return nil, fmt.Errorf("extract addresses from instance err: %v", err)
+		}
+		for _, netInterface := range interfaces {
+			addressType := corev1.NodeInternalIP
+			if netInterface.Type == "floating" {
+				addressType = corev1.NodeExternalIP
+			}
+			addresses = append(addresses, corev1.NodeAddress{Type: addressType, Address: netInterface.Address})
+		}
+	}
+	return addresses, nil
+}
+
+// GetIPFromInstance returns the addresses of the server by their kind: the
+// internal and floating IPv4 address, and the first internal IPv6 address as
+// internalv6.
 func GetIPFromInstance(v servers.Server) (map[string]string, error) {
 	addrMap := make(map[string]string)
 	if v.AccessIPv4 != "" && net.ParseIP(v.AccessIPv4) != nil {
 		addrMap["internal"] = v.AccessIPv4
+	}
+	if v.AccessIPv6 != "" && net.ParseIP(v.AccessIPv6) != nil {
+		addrMap["internalv6"] = v.AccessIPv6
+	}
+	if len(addrMap) > 0 {
 		return addrMap, nil
 	}
 	type networkInterface struct {
@@ -344,12 +775,18 @@ func GetIPFromInstance(v servers.Server) (map[string]string, error) {
 			if err != nil {
 				return nil, fmt.Errorf("extract IP from instance err: %v", err)
 			}
-			if netInterface.Version == 4.0 {
+			switch netInterface.Version {
+			case 4.0:
 				if netInterface.Type == "floating" {
 					addrMap["floating"] = netInterface.Address
 				} else {
 					addrMap["internal"] = netInterface.Address
 				}
+			case 6.0:
+				// Neutron has no IPv6 floating IPs.
+				if _, ok := addrMap["internalv6"]; !ok {
+					addrMap["internalv6"] = netInterface.Address
+				}
 			}
 		}
 	}
@@ -357,24 +794,91 @@ func GetIPFromInstance(v servers.Server) (map[string]string, error) {
 	return addrMap, nil
 }
 
-// applyRootVolume sets a root volume if the root volume Size is not 0.
-func applyRootVolume(opts servers.CreateOptsBuilder, rootVolume *infrav1.RootVolume) servers.CreateOptsBuilder {
-	if rootVolume != nil && rootVolume.Size != 0 {
-		block := bootfromvolume.BlockDevice{
+// rootVolumeType returns the volume type of the root volume of a machine in the
+// given availability zone, or an empty string if the default type is used. A
+// volume type configured for the availability zone takes precedence.
+func rootVolumeType(rootVolume *infrav1.RootVolume, availabilityZone string) string {
+	if rootVolume == nil || rootVolume.Size == 0 || bootsFromExistingVolume(rootVolume) {
+		return ""
+	}
+	if volumeType, ok := rootVolume.VolumeTypesByAvailabilityZone[availabilityZone]; ok && availabilityZone != "" {
+		return volumeType
+	}
+	return rootVolume.VolumeType
+}
+
+// bootsFromExistingVolume returns whether the instance boots from an existing
+// volume instead of a volume which is created for it.
+func bootsFromExistingVolume(rootVolume *infrav1.RootVolume) bool {
+	return rootVolume != nil && rootVolume.SourceType == string(bootfromvolume.SourceVolume)
+}
+
+// applyBlockDevices sets the block device mapping of the server: an existing
+// root volume, a root volume if the root volume Size is not 0, and the
+// additional volumes. If rootVolumeID is set, the root volume has already been
+// created and is attached as it is. Volumes with PreserveOnDelete set are kept
+// when the instance is deleted.
+func applyBlockDevices(opts servers.CreateOptsBuilder, imageID string, rootVolume *infrav1.RootVolume, rootVolumeID, volumeType string, additionalBlockDevices []infrav1.AdditionalBlockDevice, additionalVolumeIDs []string) servers.CreateOptsBuilder {
+	var blockDevices []bootfromvolume.BlockDevice
+	if bootsFromExistingVolume(rootVolume) {
+		// The volume isn't owned by the machine, so it is kept when the
+		// instance is deleted.
+		blockDevices = append(blockDevices, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceVolume,
+			BootIndex:           0,
+			UUID:                rootVolume.SourceUUID,
+			DeleteOnTermination: false,
+			DestinationType:     bootfromvolume.DestinationVolume,
+			DeviceType:          rootVolume.DeviceType,
+		})
+	} else if rootVolumeID != "" {
+		blockDevices = append(blockDevices, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceVolume,
+			BootIndex:           0,
+			UUID:                rootVolumeID,
+			DeleteOnTermination: !rootVolume.PreserveOnDelete,
+			DestinationType:     bootfromvolume.DestinationVolume,
+			DeviceType:          rootVolume.DeviceType,
+		})
+	} else if rootVolume != nil && rootVolume.Size != 0 {
+		blockDevices = append(blockDevices, bootfromvolume.BlockDevice{
 			SourceType:          bootfromvolume.SourceType(rootVolume.SourceType),
 			BootIndex:           0,
 			UUID:                rootVolume.SourceUUID,
-			DeleteOnTermination: true,
+			DeleteOnTermination: !rootVolume.PreserveOnDelete,
 			DestinationType:     bootfromvolume.DestinationVolume,
 			VolumeSize:          rootVolume.Size,
 			DeviceType:          rootVolume.DeviceType,
-		}
-		return bootfromvolume.CreateOptsExt{
-			CreateOptsBuilder: opts,
-			BlockDevice:       []bootfromvolume.BlockDevice{block},
-		}
+			VolumeType:          volumeType,
+		})
+	} else if len(additionalVolumeIDs) > 0 {
+		// The image has to be part of the block device mapping as soon as
+		// there is one.
+		blockDevices = append(blockDevices, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceImage,
+			BootIndex:           0,
+			UUID:                imageID,
+			DeleteOnTermination: true,
+			DestinationType:     bootfromvolume.DestinationLocal,
+		})
+	}
+	for idx, volumeID := range additionalVolumeIDs {
+		blockDevices = append(blockDevices, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceVolume,
+			BootIndex:           -1,
+			UUID:                volumeID,
+			DeleteOnTermination: !additionalBlockDevices[idx].PreserveOnDelete,
+			DestinationType:     bootfromvolume.DestinationVolume,
+		})
+	}
+
+	if len(blockDevices) == 0 {
+		return opts
+	}
+	return bootfromvolume.CreateOptsExt{
+		CreateOptsBuilder: opts,
+		BlockDevice:       blockDevices,
 	}
-	return opts
 }
 
 // applyServerGroupID adds a scheduler hint to the CreateOptsBuilder, if the
@@ -417,8 +921,14 @@ func getSecurityGroups(is *Service, securityGroupParams []infrav1.SecurityGroupP
 		if listOpts.ProjectID == "" {
 			listOpts.ProjectID = is.projectID
 		}
-		listOpts.Name = sg.Name
-		listOpts.ID = sg.UUID
+		// The name and the UUID take precedence over the filter, which may
+		// select the groups by their description or tags alone.
+		if sg.Name != "" {
+			listOpts.Name = sg.Name
+		}
+		if sg.UUID != "" {
+			listOpts.ID = sg.UUID
+		}
 		pages, err := groups.List(is.networkClient, listOpts).AllPages()
 		if err != nil {
 			return nil, err
@@ -430,7 +940,7 @@ func getSecurityGroups(is *Service, securityGroupParams []infrav1.SecurityGroupP
 		}
 
 		if len(SGList) == 0 {
-			return nil, fmt.Errorf("security group %s not found", sg.Name)
+			return nil, fmt.Errorf("no security group found with %+v", sg)
 		}
 
 		for _, group := range SGList {
@@ -443,23 +953,79 @@ func getSecurityGroups(is *Service, securityGroupParams []infrav1.SecurityGroupP
 	return sgIDs, nil
 }
 
+// getQoSPolicyID returns the ID of the QoS policy matching the parameters. It
+// fails unless exactly one policy matches.
+func getQoSPolicyID(is *Service, param *infrav1.QoSPolicyParam) (string, error) {
+	filter := param.Filter
+	listOpts := policies.ListOpts{
+		ID:          filter.ID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		TenantID:    filter.TenantID,
+		ProjectID:   filter.ProjectID,
+		Tags:        filter.Tags,
+		TagsAny:     filter.TagsAny,
+		NotTags:     filter.NotTags,
+		NotTagsAny:  filter.NotTagsAny,
+	}
+	if param.UUID != "" {
+		listOpts.ID = param.UUID
+	}
+	if param.Name != "" {
+		listOpts.Name = param.Name
+	}
+	pages, err := policies.List(is.networkClient, listOpts).AllPages()
+	if err != nil {
+		return "", err
+	}
+	policyList, err := policies.ExtractPolicies(pages)
+	if err != nil {
+		return "", err
+	}
+	switch len(policyList) {
+	case 0:
+		return "", fmt.Errorf("no QoS policy found with %+v", *param)
+	case 1:
+		return policyList[0].ID, nil
+	}
+	return "", fmt.Errorf("found %d QoS policies with %+v", len(policyList), *param)
+}
+
 func getServerNetworks(networkClient *gophercloud.ServiceClient, networkParams []infrav1.NetworkParam) ([]infrav1.Network, error) {
 	var nets []infrav1.Network
 	for _, networkParam := range networkParams {
 		opts := networks.ListOpts(networkParam.Filter)
 		opts.ID = networkParam.UUID
-		ids, err := networking.GetNetworkIDsByFilter(networkClient, &opts)
+		netsByFilter, err := networking.GetNetworksByFilter(networkClient, &opts)
 		if err != nil {
 			return nil, err
 		}
-		for _, netID := range ids {
+		if networkParam.FixedIP != "" && len(netsByFilter) > 1 {
+			return nil, fmt.Errorf("fixed IP %s requires exactly one network, found %d", networkParam.FixedIP, len(netsByFilter))
+		}
+		// The order of the ports must not depend on the order Neutron happens
+		// to return the networks in.
+		if opts.SortKey == "" {
+			sort.SliceStable(netsByFilter, func(i, j int) bool {
+				return lessByNameAndID(netsByFilter[i].Name, netsByFilter[i].ID, netsByFilter[j].Name, netsByFilter[j].ID)
+			})
+		}
+		for _, netByFilter := range netsByFilter {
+			netID := netByFilter.ID
 			if networkParam.Subnets == nil {
 				nets = append(nets, infrav1.Network{
-					ID: netID,
+					ID:       netID,
+					FixedIP:  networkParam.FixedIP,
+					VNICType: networkParam.VNICType,
+					Profile:  networkParam.Profile,
+
+					DisablePortSecurity: networkParam.DisablePortSecurity,
+					AllowedAddressPairs: networkParam.AllowedAddressPairs,
 				})
 				continue
 			}
 
+			fixedIPAssigned := false
 			for _, subnet := range networkParam.Subnets {
 				subnetOpts := subnets.ListOpts(subnet.Filter)
 				subnetOpts.ID = subnet.UUID
@@ -468,20 +1034,93 @@ func getServerNetworks(networkClient *gophercloud.ServiceClient, networkParams [
 				if err != nil {
 					return nil, err
 				}
+				if subnetOpts.SortKey == "" {
+					sort.SliceStable(subnetsByFilter, func(i, j int) bool {
+						return lessByNameAndID(subnetsByFilter[i].Name, subnetsByFilter[i].ID, subnetsByFilter[j].Name, subnetsByFilter[j].ID)
+					})
+				}
 				for _, subnetByFilter := range subnetsByFilter {
-					nets = append(nets, infrav1.Network{
+					network := infrav1.Network{
 						ID: subnetByFilter.NetworkID,
 						Subnet: &infrav1.Subnet{
 							ID: subnetByFilter.ID,
 						},
-					})
+						VNICType: networkParam.VNICType,
+						Profile:  networkParam.Profile,
+
+						DisablePortSecurity: networkParam.DisablePortSecurity,
+						AllowedAddressPairs: networkParam.AllowedAddressPairs,
+					}
+					if networkParam.FixedIP != "" && !fixedIPAssigned && subnetContains(subnetByFilter.CIDR, networkParam.FixedIP) {
+						network.FixedIP = networkParam.FixedIP
+						fixedIPAssigned = true
+					}
+					nets = append(nets, network)
 				}
 			}
+			if networkParam.FixedIP != "" && !fixedIPAssigned {
+				return nil, fmt.Errorf("fixed IP %s is not contained in any subnet of network %s", networkParam.FixedIP, netID)
+			}
 		}
 	}
 	return nets, nil
 }
 
+// lessByNameAndID orders networks and subnets by their name, and by their ID if
+// their names are equal.
+func lessByNameAndID(nameA, idA, nameB, idB string) bool {
+	if nameA != nameB {
+		return nameA < nameB
+	}
+	return idA < idB
+}
+
+// subnetContains returns whether the address is in the CIDR of a subnet.
+func subnetContains(cidr, address string) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	return ipNet.Contains(net.ParseIP(address))
+}
+
+// getAdditionalNetworks returns the additional networks of the cluster which
+// are attached to the machine according to its role.
+func getAdditionalNetworks(networkClient *gophercloud.ServiceClient, additionalNetworks []infrav1.AdditionalNetwork, machine *clusterv1.Machine) ([]infrav1.Network, error) {
+	role := infrav1.MachineRoleWorker
+	if util.IsControlPlaneMachine(machine) {
+		role = infrav1.MachineRoleControlPlane
+	}
+
+	var networkParams []infrav1.NetworkParam
+	for _, additionalNetwork := range additionalNetworks {
+		if len(additionalNetwork.Roles) > 0 && !hasRole(additionalNetwork.Roles, role) {
+			continue
+		}
+		if hasRole(additionalNetwork.ExcludeRoles, role) {
+			continue
+		}
+		networkParam := additionalNetwork.NetworkParam
+		// The additional networks are shared by all machines, so they can't
+		// have a fixed IP.
+		networkParam.FixedIP = ""
+		networkParams = append(networkParams, networkParam)
+	}
+	if len(networkParams) == 0 {
+		return nil, nil
+	}
+	return getServerNetworks(networkClient, networkParams)
+}
+
+func hasRole(roles []infrav1.MachineRole, role infrav1.MachineRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 func isDuplicate(list []string, name string) bool {
 	if len(list) == 0 {
 		return false
@@ -494,44 +1133,533 @@ func isDuplicate(list []string, name string) bool {
 	return false
 }
 
-func createPort(is *Service, clusterName string, name string, net *infrav1.Network, securityGroups *[]string) (ports.Port, error) {
+func createPort(is *Service, clusterName string, name string, net *infrav1.Network, securityGroups *[]string, tags []string, dnsName string) (ports.Port, error) {
+	portOpts := net.PortOpts
+	if portOpts == nil {
+		portOpts = networkPortOpts(net)
+	}
+	description := portOpts.Description
+	if description == "" {
+		description = fmt.Sprintf("Created by cluster-api-provider-openstack cluster %s", clusterName)
+	}
+	if portOpts.SecurityGroups != nil {
+		portSecurityGroups, err := getSecurityGroups(is, *portOpts.SecurityGroups)
+		if err != nil {
+			return ports.Port{}, fmt.Errorf("get security groups of port: %v", err)
+		}
+		securityGroups = &portSecurityGroups
+	}
 	portCreateOpts := ports.CreateOpts{
 		Name:           name,
 		NetworkID:      net.ID,
 		SecurityGroups: securityGroups,
-		Description:    fmt.Sprintf("Created by cluster-api-provider-openstack cluster %s", clusterName),
+		Description:    description,
+		AdminStateUp:   portOpts.AdminStateUp,
+		MACAddress:     portOpts.MACAddress,
 	}
-	if net.Subnet.ID != "" {
-		portCreateOpts.FixedIPs = []ports.IP{{SubnetID: net.Subnet.ID}}
+	var fixedIPs []ports.IP
+	var fixedIPAddresses []string
+	for _, fixedIP := range portOpts.FixedIPs {
+		fixedIPs = append(fixedIPs, ports.IP{SubnetID: fixedIP.SubnetID, IPAddress: fixedIP.IPAddress})
+		if fixedIP.IPAddress != "" {
+			fixedIPAddresses = append(fixedIPAddresses, fixedIP.IPAddress)
+		}
 	}
-	newPort, err := ports.Create(is.networkClient, portCreateOpts).Extract()
-	if err != nil {
-		return ports.Port{}, fmt.Errorf("create port for server: %v", err)
+	if len(fixedIPs) > 0 {
+		portCreateOpts.FixedIPs = fixedIPs
 	}
-	return *newPort, nil
-}
-
-func deletePorts(s *Service, nets []servers.Network) error {
-	for _, n := range nets {
-		_, err := ports.Get(s.networkClient, n.Port).Extract()
+	for _, pair := range portOpts.AllowedAddressPairs {
+		portCreateOpts.AllowedAddressPairs = append(portCreateOpts.AllowedAddressPairs, ports.AddressPair{
+			IPAddress:  pair.IPAddress,
+			MACAddress: pair.MACAddress,
+		})
+	}
+	var createOpts ports.CreateOptsBuilder = portCreateOpts
+	if portOpts.DisablePortSecurity {
+		// Neutron refuses security groups on ports without port security.
+		portCreateOpts.SecurityGroups = &[]string{}
+		createOpts = portsecurity.PortCreateOptsExt{
+			CreateOptsBuilder:   portCreateOpts,
+			PortSecurityEnabled: pointer.BoolPtr(false),
+		}
+	}
+	if portOpts.QoSPolicy != nil {
+		qosPolicyID, err := getQoSPolicyID(is, portOpts.QoSPolicy)
 		if err != nil {
-			if capoerrors.IsNotFound(err) {
-				return nil
-			}
+			return ports.Port{}, fmt.Errorf("get QoS policy of port: %v", err)
 		}
-		if err := ports.Delete(s.networkClient, n.Port).ExtractErr(); err != nil {
-			return err
+		createOpts = policies.PortCreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			QoSPolicyID:       qosPolicyID,
 		}
 	}
-	return nil
-}
-
+	if portOpts.DNSName != "" {
+		dnsName = portOpts.DNSName
+	}
+	if dnsName != "" || portOpts.DNSDomain != "" {
+		createOpts = portDNSCreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			DNSName:           dnsName,
+			DNSDomain:         portOpts.DNSDomain,
+		}
+	}
+	if portOpts.HostID != "" || portOpts.VNICType != "" || len(portOpts.Profile) > 0 {
+		var profile map[string]interface{}
+		if len(portOpts.Profile) > 0 {
+			profile = map[string]interface{}{}
+			for k, v := range portOpts.Profile {
+				profile[k] = v
+			}
+		}
+		createOpts = portsbinding.CreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			HostID:            portOpts.HostID,
+			VNICType:          portOpts.VNICType,
+			Profile:           profile,
+		}
+	}
+	newPort, err := ports.Create(is.networkClient, createOpts).Extract()
+	if err != nil {
+		if len(fixedIPAddresses) > 0 && capoerrors.IsConflict(err) {
+			return ports.Port{}, fmt.Errorf("create port for server: fixed IP %s is already in use: %v", strings.Join(fixedIPAddresses, ", "), err)
+		}
+		if portOpts.MACAddress != "" && capoerrors.IsConflict(err) {
+			return ports.Port{}, fmt.Errorf("create port for server: MAC address %s is already in use: %v", portOpts.MACAddress, err)
+		}
+		return ports.Port{}, fmt.Errorf("create port for server: %v", err)
+	}
+	if portTags := deduplicate(append(append([]string{}, portOpts.Tags...), tags...)); len(portTags) > 0 {
+		_, err = attributestags.ReplaceAll(is.networkClient, "ports", newPort.ID, attributestags.ReplaceAllOpts{
+			Tags: portTags,
+		}).Extract()
+		if err != nil {
+			return ports.Port{}, fmt.Errorf("tagging port for server err: %v", err)
+		}
+	}
+	return *newPort, nil
+}
+
+// portDNSCreateOptsExt sets the dns_name and the dns_domain of a port. The dns
+// extension of gophercloud only supports the dns_name.
+type portDNSCreateOptsExt struct {
+	ports.CreateOptsBuilder
+	DNSName   string
+	DNSDomain string
+}
+
+func (opts portDNSCreateOptsExt) ToPortCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToPortCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	port := base["port"].(map[string]interface{})
+	if opts.DNSName != "" {
+		port["dns_name"] = opts.DNSName
+	}
+	if opts.DNSDomain != "" {
+		port["dns_domain"] = opts.DNSDomain
+	}
+	return base, nil
+}
+
+// networkPortOpts returns the options of the port of an instance in a network
+// which is not defined in the Ports of the machine.
+func networkPortOpts(net *infrav1.Network) *infrav1.PortOpts {
+	portOpts := &infrav1.PortOpts{
+		VNICType:            net.VNICType,
+		Profile:             net.Profile,
+		DisablePortSecurity: net.DisablePortSecurity,
+		AllowedAddressPairs: net.AllowedAddressPairs,
+	}
+	if net.Subnet != nil && net.Subnet.ID != "" {
+		portOpts.FixedIPs = []infrav1.FixedIP{{SubnetID: net.Subnet.ID, IPAddress: net.FixedIP}}
+	} else if net.FixedIP != "" {
+		portOpts.FixedIPs = []infrav1.FixedIP{{IPAddress: net.FixedIP}}
+	}
+	if net.IPv6Subnet != nil && net.IPv6Subnet.ID != "" {
+		portOpts.FixedIPs = append(portOpts.FixedIPs, infrav1.FixedIP{SubnetID: net.IPv6Subnet.ID})
+	}
+	return portOpts
+}
+
+// clusterNetwork returns the network of the cluster with its subnets, in which
+// instances without networks or ports are created.
+func clusterNetwork(openStackCluster *infrav1.OpenStackCluster) infrav1.Network {
+	network := infrav1.Network{
+		ID: openStackCluster.Status.Network.ID,
+		Subnet: &infrav1.Subnet{
+			ID: openStackCluster.Status.Network.Subnet.ID,
+		},
+	}
+	if ipv6Subnet := openStackCluster.Status.Network.IPv6Subnet; ipv6Subnet != nil {
+		network.IPv6Subnet = &infrav1.Subnet{
+			ID: ipv6Subnet.ID,
+		}
+	}
+	return network
+}
+
+// primaryPortIndex returns the index of the network whose port is the primary
+// interface of the node, or -1 if there is none.
+func primaryPortIndex(networks []infrav1.Network) (int, error) {
+	primary := -1
+	for index := range networks {
+		if networks[index].PortOpts == nil || !networks[index].PortOpts.Primary {
+			continue
+		}
+		if primary >= 0 {
+			return -1, fmt.Errorf("ports %d and %d are both marked as primary", primary, index)
+		}
+		primary = index
+	}
+	return primary, nil
+}
+
+// accessIPs returns the access IPv4 and IPv6 addresses of the server. The fixed
+// IPs of the ports on the given subnet take precedence, the address families
+// which have none there are taken from the primary port, if there is one. It
+// also returns whether a port has a fixed IP on the subnet.
+func accessIPs(serverPorts []ports.Port, primaryPort int, subnet string) (string, string, bool) {
+	var accessIPv4, accessIPv6 string
+	onSubnet := false
+	set := func(address string) {
+		ip := net.ParseIP(address)
+		switch {
+		case ip == nil:
+		case ip.To4() != nil:
+			if accessIPv4 == "" {
+				accessIPv4 = address
+			}
+		default:
+			if accessIPv6 == "" {
+				accessIPv6 = address
+			}
+		}
+	}
+	if subnet != "" {
+		for _, port := range serverPorts {
+			for _, fip := range port.FixedIPs {
+				if fip.SubnetID == subnet {
+					set(fip.IPAddress)
+					onSubnet = true
+				}
+			}
+		}
+	}
+	if primaryPort >= 0 && primaryPort < len(serverPorts) {
+		for _, fip := range serverPorts[primaryPort].FixedIPs {
+			set(fip.IPAddress)
+		}
+	}
+	return accessIPv4, accessIPv6, onSubnet
+}
+
+// portNetworks returns the networks of the ports of a machine. Ports without a
+// network are created in the network of the cluster.
+func portNetworks(openStackCluster *infrav1.OpenStackCluster, portOpts []infrav1.PortOpts) []infrav1.Network {
+	nets := make([]infrav1.Network, 0, len(portOpts))
+	for i := range portOpts {
+		network := infrav1.Network{
+			ID:       portOpts[i].NetworkID,
+			PortOpts: portOpts[i].DeepCopy(),
+		}
+		if network.ID == "" && portOpts[i].PortID == "" && openStackCluster.Status.Network != nil {
+			network.ID = openStackCluster.Status.Network.ID
+			if openStackCluster.Status.Network.Subnet != nil {
+				network.Subnet = &infrav1.Subnet{
+					ID: openStackCluster.Status.Network.Subnet.ID,
+				}
+				if len(network.PortOpts.FixedIPs) == 0 {
+					network.PortOpts.FixedIPs = []infrav1.FixedIP{{SubnetID: network.Subnet.ID}}
+					if ipv6Subnet := openStackCluster.Status.Network.IPv6Subnet; ipv6Subnet != nil {
+						network.PortOpts.FixedIPs = append(network.PortOpts.FixedIPs, infrav1.FixedIP{SubnetID: ipv6Subnet.ID})
+					}
+				}
+			}
+		}
+		nets = append(nets, network)
+	}
+	return nets
+}
+
+// addAPIServerVIP adds the address of the API server VIP port to the allowed
+// address pairs of the ports in the network of the cluster, so that kube-vip
+// or keepalived on the control plane machines can own it.
+func addAPIServerVIP(openStackCluster *infrav1.OpenStackCluster, nets []infrav1.Network) {
+	vip := openStackCluster.Status.APIServerVIP
+	if vip == nil || openStackCluster.Spec.DisablePortSecurity || openStackCluster.Status.Network == nil {
+		return
+	}
+	pair := infrav1.AddressPair{IPAddress: vip.Address}
+	for i := range nets {
+		if nets[i].ID != openStackCluster.Status.Network.ID {
+			continue
+		}
+		if portOpts := nets[i].PortOpts; portOpts != nil {
+			if !portOpts.DisablePortSecurity {
+				portOpts.AllowedAddressPairs = append(append([]infrav1.AddressPair{}, portOpts.AllowedAddressPairs...), pair)
+			}
+		} else if !nets[i].DisablePortSecurity {
+			nets[i].AllowedAddressPairs = append(append([]infrav1.AddressPair{}, nets[i].AllowedAddressPairs...), pair)
+		}
+	}
+}
+
+// portName returns the name of a port of the machine, e.g. machine-0 for the
+// first port without a name suffix.
+func portName(instanceName string, portOpts *infrav1.PortOpts, index int) string {
+	if portOpts.NameSuffix != "" {
+		return fmt.Sprintf("%s-%s", instanceName, portOpts.NameSuffix)
+	}
+	return fmt.Sprintf("%s-%d", instanceName, index)
+}
+
+// firstPortUsesTrunk returns whether a trunk is created for the first port of
+// the machine.
+func firstPortUsesTrunk(openStackMachine *infrav1.OpenStackMachine) bool {
+	if len(openStackMachine.Spec.Ports) > 0 {
+		port := openStackMachine.Spec.Ports[0]
+		if port.PortID != "" {
+			return false
+		}
+		if port.Trunk != nil {
+			return *port.Trunk
+		}
+	}
+	return openStackMachine.Spec.Trunk
+}
+
+// reconcileSubports creates the ports of the subports and adds the missing
+// subports to the trunk. Subports which are not in the list are kept, as e.g.
+// Kuryr adds subports to the trunks itself.
+func reconcileSubports(is *Service, clusterName, instanceName string, trunk trunks.Trunk, subports []infrav1.Subport, securityGroups *[]string, portTags []string) error {
+	existing := map[int]bool{}
+	for _, subport := range trunk.Subports {
+		existing[subport.SegmentationID] = true
+	}
+
+	var missing []trunks.Subport
+	for _, subport := range subports {
+		if existing[subport.SegmentationID] {
+			continue
+		}
+		name := subportName(instanceName, subport.SegmentationID)
+		allPages, err := ports.List(is.networkClient, ports.ListOpts{
+			Name:      name,
+			NetworkID: subport.NetworkID,
+		}).AllPages()
+		if err != nil {
+			return fmt.Errorf("searching for existing subport for server: %v", err)
+		}
+		portList, err := ports.ExtractPorts(allPages)
+		if err != nil {
+			return fmt.Errorf("searching for existing subport for server err: %v", err)
+		}
+		var port ports.Port
+		if len(portList) == 0 {
+			network := infrav1.Network{ID: subport.NetworkID}
+			if subport.SubnetID != "" {
+				network.Subnet = &infrav1.Subnet{ID: subport.SubnetID}
+			}
+			port, err = createPort(is, clusterName, name, &network, securityGroups, portTags, "")
+			if err != nil {
+				return fmt.Errorf("failed to create subport err: %v", err)
+			}
+		} else {
+			port = portList[0]
+		}
+		missing = append(missing, trunks.Subport{
+			PortID:           port.ID,
+			SegmentationID:   subport.SegmentationID,
+			SegmentationType: "vlan",
+		})
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	_, err := trunks.AddSubports(is.networkClient, trunk.ID, trunks.AddSubportsOpts{
+		Subports: missing,
+	}).Extract()
+	if err != nil {
+		return fmt.Errorf("add subports to trunk for server err: %v", err)
+	}
+	return nil
+}
+
+// subportName returns the name of the port of a subport, e.g. machine-subport-100
+// for the VLAN 100.
+func subportName(instanceName string, segmentationID int) string {
+	return fmt.Sprintf("%s-subport-%d", instanceName, segmentationID)
+}
+
+// existingPortIDs returns the IDs of the existing ports of a machine, which are
+// not deleted with the machine.
+func existingPortIDs(portOpts []infrav1.PortOpts) []string {
+	var ids []string
+	for _, port := range portOpts {
+		if port.PortID != "" {
+			ids = append(ids, port.PortID)
+		}
+	}
+	return ids
+}
+
+// portsUseTrunk returns whether any of the ports requests a trunk.
+func portsUseTrunk(portOpts []infrav1.PortOpts) bool {
+	for _, port := range portOpts {
+		if port.Trunk != nil && *port.Trunk {
+			return true
+		}
+	}
+	return false
+}
+
+// machinePortTag returns the tag of the ports created for the machine. It
+// contains the UID of the machine, as Neutron limits tags to 60 characters.
+func machinePortTag(openStackMachine *infrav1.OpenStackMachine) string {
+	return fmt.Sprintf("capo-machine-%s", openStackMachine.UID)
+}
+
+// portIndexTagPrefix prefixes the tag which carries the index of a port.
+const portIndexTagPrefix = "capo-port-index="
+
+// getUnindexedPorts returns the ports with the given name in the network which
+// don't carry an index tag yet. The first of them is tagged with the given
+// index tag, so that it is found by its index from now on.
+func getUnindexedPorts(is *Service, name, networkID, indexTag string) ([]ports.Port, error) {
+	allPages, err := ports.List(is.networkClient, ports.ListOpts{
+		Name:      name,
+		NetworkID: networkID,
+	}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("searching for existing port for server: %v", err)
+	}
+	portList, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("searching for existing port for server err: %v", err)
+	}
+
+	var unindexed []ports.Port
+	for _, port := range portList {
+		if !hasPortIndexTag(port.Tags) {
+			unindexed = append(unindexed, port)
+		}
+	}
+	if len(unindexed) == 0 {
+		return nil, nil
+	}
+
+	port := &unindexed[0]
+	_, err = attributestags.ReplaceAll(is.networkClient, "ports", port.ID, attributestags.ReplaceAllOpts{
+		Tags: append(append([]string{}, port.Tags...), indexTag),
+	}).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("tagging port for server err: %v", err)
+	}
+	port.Tags = append(port.Tags, indexTag)
+	return unindexed, nil
+}
+
+func hasPortIndexTag(tags []string) bool {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, portIndexTagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// portIndexTag returns the tag of the port of the machine with the given index.
+// The ports are attached to the server in the order of their index, which
+// determines the order of the interfaces in the guest.
+func portIndexTag(index int) string {
+	return fmt.Sprintf("%s%d", portIndexTagPrefix, index)
+}
+
+// DeleteOrphanedPorts deletes the ports created for the machine which are not
+// attached to a server, e.g. because the controller stopped between the
+// creation of the ports and of the server, together with their trunks.
+func (s *Service) DeleteOrphanedPorts(openStackMachine *infrav1.OpenStackMachine, timeouts *infrav1.Timeouts) error {
+	allPages, err := ports.List(s.networkClient, ports.ListOpts{
+		Tags: machinePortTag(openStackMachine),
+	}).AllPages()
+	if err != nil {
+		return fmt.Errorf("searching for orphaned ports of machine %s: %v", openStackMachine.Name, err)
+	}
+	portList, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return fmt.Errorf("searching for orphaned ports of machine %s: %v", openStackMachine.Name, err)
+	}
+
+	var trunkSupport *bool
+	for _, port := range portList {
+		// The ports of subports are attached to their trunk, and deleted
+		// with it.
+		if port.DeviceID != "" {
+			continue
+		}
+		if trunkSupport == nil {
+			supported, err := getTrunkSupport(s)
+			if err != nil {
+				return fmt.Errorf("obtaining network extensions: %v", err)
+			}
+			trunkSupport = &supported
+		}
+		if *trunkSupport {
+			allTrunks, err := trunks.List(s.networkClient, trunks.ListOpts{PortID: port.ID}).AllPages()
+			if err != nil {
+				return err
+			}
+			trunkList, err := trunks.ExtractTrunks(allTrunks)
+			if err != nil {
+				return err
+			}
+			for _, trunk := range trunkList {
+				if err := trunks.Delete(s.networkClient, trunk.ID).ExtractErr(); err != nil && !capoerrors.IsNotFound(err) {
+					return fmt.Errorf("error deleting the trunk %v", trunk.ID)
+				}
+				if err := deleteSubportPorts(s, trunk, timeouts); err != nil {
+					return err
+				}
+			}
+		}
+		if err := ports.Delete(s.networkClient, port.ID).ExtractErr(); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(openStackMachine, "FailedDeletePort", "Failed to delete orphaned port %s with id %s: %v", port.Name, port.ID, err)
+			return err
+		}
+		record.Eventf(openStackMachine, "SuccessfulDeletePort", "Deleted orphaned port %s with id %s", port.Name, port.ID)
+	}
+	return nil
+}
+
+func deletePorts(s *Service, nets []servers.Network) error {
+	for _, n := range nets {
+		_, err := ports.Get(s.networkClient, n.Port).Extract()
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return nil
+			}
+		}
+		if err := ports.Delete(s.networkClient, n.Port).ExtractErr(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Helper function for getting image ID from name.
 func getImageID(is *Service, imageName string) (string, error) {
 	if imageName == "" {
 		return "", nil
 	}
 
+	return is.cachedLookup("image", is.imagesClient.Endpoint, imageName, func() (string, error) {
+		return lookupImageID(is, imageName)
+	})
+}
+
+func lookupImageID(is *Service, imageName string) (string, error) {
 	opts := images.ListOpts{
 		Name: imageName,
 	}
@@ -556,6 +1684,171 @@ func getImageID(is *Service, imageName string) (string, error) {
 	}
 }
 
+// resolveImageID returns the ID of the image given either by its ID, a filter
+// or its name.
+func resolveImageID(is *Service, imageUUID string, imageFilter *infrav1.ImageFilter, imageName string) (string, error) {
+	switch {
+	case imageUUID != "":
+		return imageUUID, nil
+	case imageFilter != nil:
+		return getImageIDByFilter(is, imageFilter)
+	default:
+		return getImageID(is, imageName)
+	}
+}
+
+// getImageIDByFilter returns the ID of the image which matches the filter. The
+// properties are matched by the client, as Glance can't filter by them.
+func getImageIDByFilter(is *Service, filter *infrav1.ImageFilter) (string, error) {
+	opts := images.ListOpts{
+		Name:       filter.Name,
+		Tags:       filter.Tags,
+		Visibility: images.ImageVisibility(filter.Visibility),
+	}
+
+	pages, err := images.List(is.imagesClient, opts).AllPages()
+	if err != nil {
+		return "", err
+	}
+
+	allImages, err := images.ExtractImages(pages)
+	if err != nil {
+		return "", err
+	}
+
+	var matchingImages []images.Image
+	for _, image := range allImages {
+		if imageHasProperties(image, filter.Properties) {
+			matchingImages = append(matchingImages, image)
+		}
+	}
+
+	switch {
+	case len(matchingImages) == 0:
+		return "", fmt.Errorf("no image matching the filter %+v could be found", *filter)
+	case len(matchingImages) == 1:
+		return matchingImages[0].ID, nil
+	case filter.MostRecent:
+		sort.SliceStable(matchingImages, func(a, b int) bool {
+			return matchingImages[a].CreatedAt.After(matchingImages[b].CreatedAt)
+		})
+		return matchingImages[0].ID, nil
+	default:
+		return "", fmt.Errorf("too many images matching the filter %+v were found", *filter)
+	}
+}
+
+func imageHasProperties(image images.Image, properties map[string]string) bool {
+	for key, value := range properties {
+		property, ok := image.Properties[key]
+		if !ok || fmt.Sprint(property) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// getFlavorID returns the ID of the flavor with the given name. Private
+// flavors are only used if they are shared with the project.
+func getFlavorID(is *Service, flavorName string) (string, error) {
+	return is.cachedLookup("flavor", is.computeClient.Endpoint, flavorName, func() (string, error) {
+		return lookupFlavorID(is, flavorName)
+	})
+}
+
+func lookupFlavorID(is *Service, flavorName string) (string, error) {
+	// Without the access type, Nova only lists public flavors to administrators.
+	pages, err := flavors.ListDetail(is.computeClient, flavors.ListOpts{AccessType: flavors.AllAccess}).AllPages()
+	if err != nil {
+		return "", err
+	}
+
+	allFlavors, err := flavors.ExtractFlavors(pages)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []flavors.Flavor
+	for _, flavor := range allFlavors {
+		if flavor.Name == flavorName {
+			matches = append(matches, flavor)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no flavor with the name %s could be found, private flavors must be shared with project %s", flavorName, is.projectID)
+	}
+	if len(matches) > 1 {
+		return "", fmt.Errorf("too many flavors with the name, %s, were found", flavorName)
+	}
+
+	flavor := matches[0]
+	if flavor.IsPublic {
+		return flavor.ID, nil
+	}
+	accessible, err := isFlavorAccessible(is, flavor.ID)
+	if err != nil {
+		return "", err
+	}
+	if !accessible {
+		return "", fmt.Errorf("flavor %s is private and not shared with project %s", flavorName, is.projectID)
+	}
+	return flavor.ID, nil
+}
+
+// isFlavorAccessible reports whether the private flavor is shared with the
+// project. Only administrators, who also list flavors of other projects, are
+// allowed to see the flavor access list; the flavors listed to everybody else
+// are accessible.
+func isFlavorAccessible(is *Service, flavorID string) (bool, error) {
+	pages, err := flavors.ListAccesses(is.computeClient, flavorID).AllPages()
+	if err != nil {
+		if capoerrors.IsNotFound(err) || capoerrors.IsForbidden(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("list access of flavor %s: %v", flavorID, err)
+	}
+
+	accesses, err := flavors.ExtractAccesses(pages)
+	if err != nil {
+		return false, fmt.Errorf("extract access of flavor %s: %v", flavorID, err)
+	}
+	for _, access := range accesses {
+		if access.TenantID == is.projectID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getServerGroupID(is *Service, serverGroupName string) (string, error) {
+	pages, err := servergroups.List(is.computeClient).AllPages()
+	if err != nil {
+		return "", fmt.Errorf("list server groups: %v", err)
+	}
+
+	allServerGroups, err := servergroups.ExtractServerGroups(pages)
+	if err != nil {
+		return "", fmt.Errorf("extract server groups: %v", err)
+	}
+
+	var serverGroupIDs []string
+	for _, serverGroup := range allServerGroups {
+		if serverGroup.Name == serverGroupName {
+			serverGroupIDs = append(serverGroupIDs, serverGroup.ID)
+		}
+	}
+
+	switch len(serverGroupIDs) {
+	case 0:
+		return "", fmt.Errorf("no server group with the name %s could be found", serverGroupName)
+	case 1:
+		return serverGroupIDs[0], nil
+	default:
+		return "", fmt.Errorf("too many server groups with the name, %s, were found", serverGroupName)
+	}
+}
+
 func (s *Service) AssociateFloatingIP(instanceID, floatingIP string) error {
 	opts := floatingips.AssociateOpts{
 		FloatingIP: floatingIP,
@@ -567,7 +1860,7 @@ func (s *Service) AssociateFloatingIP(instanceID, floatingIP string) error {
 	return nil
 }
 
-func (s *Service) InstanceDelete(machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) error {
+func (s *Service) InstanceDelete(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) error {
 	if machine.Spec.ProviderID == nil {
 		// nothing to do
 		return nil
@@ -577,12 +1870,25 @@ func (s *Service) InstanceDelete(machine *clusterv1.Machine, openStackMachine *i
 	if err != nil {
 		return err
 	}
-	if err = deleteInstance(s, parsed.ID()); err != nil {
+	if openStackMachine.Spec.StopBeforeDelete {
+		if err = s.stopInstance(openStackMachine, parsed.ID(), openStackCluster.Spec.Timeouts); err != nil {
+			record.Warnf(openStackMachine, "FailedStopServer", "Failed to stop server %s with id %s: %v", openStackMachine.Name, parsed.ID(), err)
+			return err
+		}
+	}
+	if err = s.detachVolumes(openStackMachine, parsed.ID()); err != nil {
+		record.Warnf(openStackMachine, "FailedDetachVolumes", "Failed to detach volumes of server %s with id %s: %v", openStackMachine.Name, parsed.ID(), err)
+		return err
+	}
+	if err = deleteInstance(s, parsed.ID(), existingPortIDs(openStackMachine.Spec.Ports), openStackCluster.Spec.Timeouts); err != nil {
+		if failure := s.InstanceActionFailure(parsed.ID()); failure != "" {
+			err = fmt.Errorf("%v: %s", err, failure)
+		}
 		record.Warnf(openStackMachine, "FailedDeleteServer", "Failed to deleted server %s with id %s: %v", openStackMachine.Name, parsed.ID(), err)
 		return err
 	}
 
-	err = util.PollImmediate(RetryIntervalInstanceStatus, TimeoutInstanceDelete, func() (bool, error) {
+	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceDeleteTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
 		_, err = s.GetInstance(parsed.ID())
 		if err != nil {
 			if capoerrors.IsNotFound(err) {
@@ -593,6 +1899,9 @@ func (s *Service) InstanceDelete(machine *clusterv1.Machine, openStackMachine *i
 		return false, nil
 	})
 	if err != nil {
+		if failure := s.InstanceActionFailure(parsed.ID()); failure != "" {
+			err = fmt.Errorf("%v: %s", err, failure)
+		}
 		record.Warnf(openStackMachine, "FailedDeleteServer", "Failed to deleted server %s with id %s: %v", openStackMachine.Name, parsed.ID(), err)
 		return fmt.Errorf("error deleting Openstack instance %s, %v", parsed.ID(), err)
 	}
@@ -601,61 +1910,82 @@ func (s *Service) InstanceDelete(machine *clusterv1.Machine, openStackMachine *i
 	return nil
 }
 
-func deleteInstance(is *Service, serverID string) error {
-	// get instance port id
-	allInterfaces, err := attachinterfaces.List(is.computeClient, serverID).AllPages()
-	if err != nil {
+// DeleteFailedInstance deletes a server of the machine which went into ERROR
+// state while it was created, so that it can be created again.
+func (s *Service) DeleteFailedInstance(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) error {
+	if err := deleteInstance(s, instance.ID, existingPortIDs(openStackMachine.Spec.Ports), openStackCluster.Spec.Timeouts); err != nil {
+		record.Warnf(openStackMachine, "FailedDeleteServer", "Failed to delete server %s with id %s: %v", instance.Name, instance.ID, err)
 		return err
 	}
-	instanceInterfaces, err := attachinterfaces.ExtractInterfaces(allInterfaces)
+
+	err := util.PollImmediate(RetryIntervalInstanceStatus, instanceDeleteTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
+		i, err := s.GetInstance(instance.ID)
+		if err != nil {
+			return false, err
+		}
+		return i == nil, nil
+	})
 	if err != nil {
+		record.Warnf(openStackMachine, "FailedDeleteServer", "Failed to delete server %s with id %s: %v", instance.Name, instance.ID, err)
+		return fmt.Errorf("error deleting Openstack instance %s, %v", instance.ID, err)
+	}
+
+	record.Eventf(openStackMachine, "SuccessfulDeleteServer", "Deleted server %s with id %s", instance.Name, instance.ID)
+	return nil
+}
+
+// stopInstance stops the server and waits until it is shut off, so that the
+// guest OS shuts down cleanly before the server is deleted.
+func (s *Service) stopInstance(openStackMachine *infrav1.OpenStackMachine, serverID string, timeouts *infrav1.Timeouts) error {
+	instance, err := s.GetInstance(serverID)
+	if err != nil || instance == nil {
 		return err
 	}
-	if len(instanceInterfaces) < 1 {
-		return servers.Delete(is.computeClient, serverID).ExtractErr()
+	if instance.State == infrav1.InstanceStateShutoff {
+		return nil
 	}
 
-	trunkSupport, err := getTrunkSupport(is)
+	// A locked instance can't be stopped.
+	if err := lockunlock.Unlock(s.computeClient, serverID).ExtractErr(); err != nil {
+		return fmt.Errorf("error unlocking the instance %s: %v", serverID, err)
+	}
+	if instance.State == infrav1.InstanceStateActive {
+		if err := startstop.Stop(s.computeClient, serverID).ExtractErr(); err != nil {
+			return fmt.Errorf("error stopping the instance %s: %v", serverID, err)
+		}
+		record.Eventf(openStackMachine, "SuccessfulStopServer", "Stopped server %s with id %s", openStackMachine.Name, serverID)
+	}
+
+	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceStopTimeout(timeouts), func() (bool, error) {
+		instance, err := s.GetInstance(serverID)
+		if err != nil {
+			return false, err
+		}
+		return instance == nil || instance.State == infrav1.InstanceStateShutoff, nil
+	})
 	if err != nil {
-		return fmt.Errorf("obtaining network extensions: %v", err)
+		return fmt.Errorf("error waiting for the instance %s to shut off: %v", serverID, err)
 	}
-	// get and delete trunks
-	for _, port := range instanceInterfaces {
-		err := attachinterfaces.Delete(is.computeClient, serverID, port.PortID).ExtractErr()
+	return nil
+}
+
+// deleteSubportPorts deletes the ports of the subports of a deleted trunk which
+// were created for the subports of the machine. Ports of other subports, e.g.
+// created by Kuryr, are left to their owner.
+func deleteSubportPorts(is *Service, trunk trunks.Trunk, timeouts *infrav1.Timeouts) error {
+	for _, subport := range trunk.Subports {
+		port, err := ports.Get(is.networkClient, subport.PortID).Extract()
 		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				continue
+			}
 			return err
 		}
-		if trunkSupport {
-			listOpts := trunks.ListOpts{
-				PortID: port.PortID,
-			}
-			allTrunks, err := trunks.List(is.networkClient, listOpts).AllPages()
-			if err != nil {
-				return err
-			}
-			trunkInfo, err := trunks.ExtractTrunks(allTrunks)
-			if err != nil {
-				return err
-			}
-			if len(trunkInfo) == 1 {
-				err = util.PollImmediate(RetryIntervalTrunkDelete, TimeoutTrunkDelete, func() (bool, error) {
-					if err := trunks.Delete(is.networkClient, trunkInfo[0].ID).ExtractErr(); err != nil {
-						if capoerrors.IsRetryable(err) {
-							return false, nil
-						}
-						return false, err
-					}
-					return true, nil
-				})
-				if err != nil {
-					return fmt.Errorf("error deleting the trunk %v", trunkInfo[0].ID)
-				}
-			}
+		if port.Name != subportName(trunk.Name, subport.SegmentationID) {
+			continue
 		}
-
-		// delete port
-		err = util.PollImmediate(RetryIntervalPortDelete, TimeoutPortDelete, func() (bool, error) {
-			err := ports.Delete(is.networkClient, port.PortID).ExtractErr()
+		err = util.PollImmediate(RetryIntervalPortDelete, portDeleteTimeout(timeouts), func() (bool, error) {
+			err := ports.Delete(is.networkClient, port.ID).ExtractErr()
 			if err != nil {
 				if capoerrors.IsRetryable(err) {
 					return false, nil
@@ -665,8 +1995,113 @@ func deleteInstance(is *Service, serverID string) error {
 			return true, nil
 		})
 		if err != nil {
-			return fmt.Errorf("error deleting the port %v", port.PortID)
+			return fmt.Errorf("error deleting the subport %v", port.ID)
+		}
+	}
+	return nil
+}
+
+// deleteInterface detaches the port from the instance and deletes it together
+// with its trunk, unless the port is in keepPortIDs.
+func deleteInterface(is *Service, serverID, portID string, keepPortIDs []string, trunkSupport bool, timeouts *infrav1.Timeouts) error {
+	err := attachinterfaces.Delete(is.computeClient, serverID, portID).ExtractErr()
+	if err != nil {
+		return err
+	}
+	if contains(keepPortIDs, portID) {
+		return nil
+	}
+	if trunkSupport {
+		listOpts := trunks.ListOpts{
+			PortID: portID,
+		}
+		allTrunks, err := trunks.List(is.networkClient, listOpts).AllPages()
+		if err != nil {
+			return err
+		}
+		trunkInfo, err := trunks.ExtractTrunks(allTrunks)
+		if err != nil {
+			return err
+		}
+		if len(trunkInfo) == 1 {
+			err = util.PollImmediate(RetryIntervalTrunkDelete, trunkDeleteTimeout(timeouts), func() (bool, error) {
+				if err := trunks.Delete(is.networkClient, trunkInfo[0].ID).ExtractErr(); err != nil {
+					if capoerrors.IsRetryable(err) {
+						return false, nil
+					}
+					return false, err
+				}
+				return true, nil
+			})
+			if err != nil {
+				return fmt.Errorf("error deleting the trunk %v", trunkInfo[0].ID)
+			}
+			if err := deleteSubportPorts(is, trunkInfo[0], timeouts); err != nil {
+				return err
+			}
+		}
+	}
+
+	// delete port
+	err = util.PollImmediate(RetryIntervalPortDelete, portDeleteTimeout(timeouts), func() (bool, error) {
+		err := ports.Delete(is.networkClient, portID).ExtractErr()
+		if err != nil {
+			if capoerrors.IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
 		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting the port %v", portID)
+	}
+	return nil
+}
+
+// deleteInstance deletes the instance with its ports and trunks. The ports in
+// keepPortIDs are only detached from the instance.
+func deleteInstance(is *Service, serverID string, keepPortIDs []string, timeouts *infrav1.Timeouts) error {
+	// The instance may have been locked while LockInstances was set. A locked
+	// instance can neither be deleted nor have its interfaces detached.
+	if err := lockunlock.Unlock(is.computeClient, serverID).ExtractErr(); err != nil {
+		return fmt.Errorf("error unlocking the instance %s: %v", serverID, err)
+	}
+
+	// get instance port id
+	allInterfaces, err := attachinterfaces.List(is.computeClient, serverID).AllPages()
+	if err != nil {
+		return err
+	}
+	instanceInterfaces, err := attachinterfaces.ExtractInterfaces(allInterfaces)
+	if err != nil {
+		return err
+	}
+	if len(instanceInterfaces) < 1 {
+		return servers.Delete(is.computeClient, serverID).ExtractErr()
+	}
+
+	trunkSupport, err := getTrunkSupport(is)
+	if err != nil {
+		return fmt.Errorf("obtaining network extensions: %v", err)
+	}
+	// Delete the interfaces with their trunks and ports concurrently, as each
+	// of them may take minutes to retry.
+	errs := make([]error, len(instanceInterfaces))
+	sem := make(chan struct{}, maxConcurrentInterfaceDeletes)
+	var wg sync.WaitGroup
+	for i := range instanceInterfaces {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = deleteInterface(is, serverID, instanceInterfaces[i].PortID, keepPortIDs, trunkSupport, timeouts)
+		}(i)
+	}
+	wg.Wait()
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return err
 	}
 
 	// delete instance
@@ -677,54 +2112,103 @@ func (s *Service) GetInstance(resourceID string) (instance *infrav1.Instance, er
 	if resourceID == "" {
 		return nil, fmt.Errorf("resourceId should be specified to get detail")
 	}
-	server, err := servers.Get(s.computeClient, resourceID).Extract()
+	var server extendedServer
+	err = servers.Get(s.computeClient, resourceID).ExtractInto(&server)
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("get server %q detail failed: %v", resourceID, err)
 	}
-	i, err := serverToInstance(server)
+	i, err := serverToInstance(&server)
 	if err != nil {
 		return nil, err
 	}
 	return i, err
 }
 
-func (s *Service) InstanceExists(name string) (instance *infrav1.Instance, err error) {
-	var listOpts servers.ListOpts
-	if name != "" {
-		listOpts = servers.ListOpts{
-			// The name parameter to /servers is a regular expression. Unless we
-			// explicitly specify a whole string match this will be a substring
-			// match.
-			Name: fmt.Sprintf("^%s$", name),
+// InstanceExists returns the server with the given name, or nil if there is
+// none. The servers carrying all of the given tags are searched first, which
+// keeps the lookup cheap in projects with many servers. If none of them has
+// the name, e.g. because the tags of the cluster were changed after the
+// server was created, all servers are searched. Clouds whose compute API
+// doesn't support filtering by tags are searched without them.
+func (s *Service) InstanceExists(name string, tags []string) (instance *infrav1.Instance, err error) {
+	if len(tags) > 0 {
+		maximum, err := s.maxComputeMicroversion()
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		listOpts = servers.ListOpts{}
+		tagsSupported, err := microversion.AtLeast(maximum, computeMicroversionTags)
+		if err != nil {
+			return nil, err
+		}
+		if tagsSupported {
+			client := *s.computeClient
+			client.Microversion = computeMicroversionTags
+			instance, err = s.findInstance(&client, name, strings.Join(tags, ","))
+			if err != nil || instance != nil {
+				return instance, err
+			}
+		}
+	}
+	return s.findInstance(s.computeClient, name, "")
+}
+
+// findInstance returns the first server with the given name and tags. The
+// server list is iterated page by page, and the iteration stops at the first
+// server. The client has to support filtering by tags if tags are given.
+func (s *Service) findInstance(client *gophercloud.ServiceClient, name, tags string) (*infrav1.Instance, error) {
+	listOpts := servers.ListOpts{
+		Tags: tags,
+	}
+	if name != "" {
+		// The name parameter to /servers is a regular expression. Unless we
+		// explicitly specify a whole string match this will be a substring
+		// match.
+		listOpts.Name = fmt.Sprintf("^%s$", name)
 	}
 
-	allPages, err := servers.List(s.computeClient, listOpts).AllPages()
+	var instance *infrav1.Instance
+	err := servers.List(client, listOpts).EachPage(func(page pagination.Page) (bool, error) {
+		var serverList []extendedServer
+		if err := servers.ExtractServersInto(page, &serverList); err != nil {
+			return false, fmt.Errorf("extract server list: %v", err)
+		}
+		if len(serverList) == 0 {
+			return true, nil
+		}
+		var err error
+		instance, err = serverToInstance(&serverList[0])
+		return false, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get server list: %v", err)
 	}
-	serverList, err := servers.ExtractServers(allPages)
+	return instance, nil
+}
+
+// GetInstanceMetadata returns the metadata of the server with the given ID.
+func (s *Service) GetInstanceMetadata(id string) (map[string]string, error) {
+	metadata, err := servers.Metadata(s.computeClient, id).Extract()
 	if err != nil {
-		return nil, fmt.Errorf("extract server list: %v", err)
+		return nil, fmt.Errorf("get metadata of server %q: %v", id, err)
 	}
-	instanceList := []*infrav1.Instance{}
-	for _, server := range serverList {
-		server := server
-		i, err := serverToInstance(&server)
-		if err != nil {
-			return nil, err
+	return metadata, nil
+}
+
+// InstanceIDExists reports whether the server with the given ID still exists.
+// Only the server itself is requested, which keeps the check cheap in projects
+// with many servers, and only a server which is not found counts as deleted.
+func (s *Service) InstanceIDExists(id string) (bool, error) {
+	err := servers.Get(s.computeClient, id).Err
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return false, nil
 		}
-		instanceList = append(instanceList, i)
-	}
-	if len(instanceList) == 0 {
-		return nil, nil
+		return false, fmt.Errorf("get server %q: %v", id, err)
 	}
-	return instanceList[0], nil
+	return true, nil
 }
 
 // deduplicate takes a slice of input strings and filters out any duplicate
@@ -744,6 +2228,15 @@ func deduplicate(sequence []string) []string {
 	return unique
 }
 
+func contains(arr []string, target string) bool {
+	for _, a := range arr {
+		if a == target {
+			return true
+		}
+	}
+	return false
+}
+
 func getTimeout(name string, timeout int) time.Duration {
 	if v := os.Getenv(name); v != "" {
 		timeout, err := strconv.Atoi(v)
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+)
+
+// instanceActionEventResultError is the result Nova records for an instance
+// action event which failed.
+const instanceActionEventResultError = "Error"
+
+// InstanceActionFailure returns a description of the most recent failed
+// instance action of the server, as recorded by Nova's os-instance-actions
+// API. The description names the failing action and event together with the
+// host it ran on, which tells whether the scheduler, the compute agent or the
+// port binding failed. Host and traceback are only exposed to administrators
+// by the default Nova policy.
+// The lookup is best effort: an empty string is returned if no failed action
+// is recorded or the history cannot be retrieved.
+func (s *Service) InstanceActionFailure(serverID string) string {
+	failure, err := getInstanceActionFailure(s, serverID)
+	if err != nil {
+		s.logger.Info("Failed to get instance action history", "serverID", serverID, "error", err.Error())
+		return ""
+	}
+	return failure
+}
+
+func getInstanceActionFailure(is *Service, serverID string) (string, error) {
+	allPages, err := instanceactions.List(is.computeClient, serverID, nil).AllPages()
+	if err != nil {
+		return "", fmt.Errorf("list instance actions of server %q: %v", serverID, err)
+	}
+	actions, err := instanceactions.ExtractInstanceActions(allPages)
+	if err != nil {
+		return "", fmt.Errorf("extract instance actions of server %q: %v", serverID, err)
+	}
+
+	// Nova returns the actions ordered by start time, the most recent first.
+	for _, action := range actions {
+		detail, err := instanceactions.Get(is.computeClient, serverID, action.RequestID).Extract()
+		if err != nil {
+			return "", fmt.Errorf("get instance action %q of server %q: %v", action.RequestID, serverID, err)
+		}
+		for _, event := range detail.Events {
+			if event.Result != instanceActionEventResultError {
+				continue
+			}
+			failure := fmt.Sprintf("instance action %q (request %s) failed in event %q", detail.Action, detail.RequestID, event.Event)
+			if event.Host != "" {
+				failure += fmt.Sprintf(" on host %q", event.Host)
+			}
+			if cause := lastLine(event.Traceback); cause != "" {
+				failure += fmt.Sprintf(": %s", cause)
+			}
+			return failure, nil
+		}
+		// The message of an action is only set if it failed.
+		if detail.Message != "" {
+			return fmt.Sprintf("instance action %q (request %s) failed: %s", detail.Action, detail.RequestID, detail.Message), nil
+		}
+	}
+	return "", nil
+}
+
+// InstanceFault returns the fault Nova recorded for a server in ERROR state,
+// e.g. "No valid host was found". Like InstanceActionFailure, the lookup is
+// best effort: an empty string is returned if the server has no fault or it
+// cannot be retrieved.
+func (s *Service) InstanceFault(serverID string) string {
+	server, err := servers.Get(s.computeClient, serverID).Extract()
+	if err != nil {
+		s.logger.Info("Failed to get server fault", "serverID", serverID, "error", err.Error())
+		return ""
+	}
+	if server.Fault.Message == "" {
+		return ""
+	}
+	return fmt.Sprintf("fault %d: %s", server.Fault.Code, server.Fault.Message)
+}
+
+// lastLine returns the last non-empty line of a Python traceback, which
+// carries the exception that was raised.
+func lastLine(traceback string) string {
+	lines := strings.Split(strings.TrimSpace(traceback), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
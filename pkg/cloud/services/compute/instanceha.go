@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+const (
+	// instanceHAServiceType is the catalog type of Masakari.
+	instanceHAServiceType = "instance-ha"
+
+	// instanceHAMetadataKey is the server metadata Masakari's instance
+	// monitor checks to decide whether a server is recovered.
+	instanceHAMetadataKey   = "HA_Enabled"
+	instanceHAMetadataValue = "True"
+
+	instanceHANotificationTypeVM       = "VM"
+	instanceHANotificationStatusFailed = "failed"
+)
+
+type instanceHANotification struct {
+	NotificationUUID string `json:"notification_uuid"`
+	Status           string `json:"status"`
+	GeneratedTime    string `json:"generated_time"`
+	Payload          struct {
+		InstanceUUID string `json:"instance_uuid"`
+		Event        string `json:"event"`
+	} `json:"payload"`
+}
+
+// InstanceHAFailure returns a description of the most recent Masakari
+// notification of the server generated after since whose recovery failed.
+// An empty string is returned if Masakari didn't fail to recover the server.
+func (s *Service) InstanceHAFailure(serverID string, since time.Time) (string, error) {
+	client, err := s.newInstanceHAClient()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("type", instanceHANotificationTypeVM)
+	query.Set("generated-since", since.UTC().Format("2006-01-02T15:04:05"))
+	query.Set("sort_key", "generated_time")
+	query.Set("sort_dir", "desc")
+
+	var result struct {
+		Notifications []instanceHANotification `json:"notifications"`
+	}
+	if _, err := client.Get(client.ServiceURL("notifications")+"?"+query.Encode(), &result, nil); err != nil {
+		return "", fmt.Errorf("list instance HA notifications: %v", err)
+	}
+	for _, notification := range result.Notifications {
+		if notification.Payload.InstanceUUID != serverID {
+			continue
+		}
+		if notification.Status != instanceHANotificationStatusFailed {
+			return "", nil
+		}
+		return fmt.Sprintf("recovery of event %q failed (notification %s at %s)", notification.Payload.Event, notification.NotificationUUID, notification.GeneratedTime), nil
+	}
+	return "", nil
+}
+
+// newInstanceHAClient returns a client for Masakari. gophercloud doesn't
+// support Masakari, so only the endpoint is looked up in the catalog.
+func (s *Service) newInstanceHAClient() (*gophercloud.ServiceClient, error) {
+	eo := gophercloud.EndpointOpts{
+		Region: s.regionName,
+	}
+	eo.ApplyDefaults(instanceHAServiceType)
+	endpoint, err := s.provider.EndpointLocator(eo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find instance HA endpoint: %v", err)
+	}
+	return &gophercloud.ServiceClient{
+		ProviderClient: s.provider,
+		Endpoint:       endpoint,
+		Type:           instanceHAServiceType,
+	}, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+func keyPairName(clusterName string) string {
+	return fmt.Sprintf("k8s-cluster-%s-keypair", clusterName)
+}
+
+// sshKeyName returns the given keypair name, falling back to the keypair
+// created from the SSHPublicKey of the cluster.
+func sshKeyName(openStackCluster *infrav1.OpenStackCluster, name string) string {
+	if name != "" {
+		return name
+	}
+	return openStackCluster.Status.SSHKeyName
+}
+
+// ReconcileKeyPair makes sure that the keypair of the cluster exists with the
+// SSHPublicKey of the cluster. As keypairs can't be updated, a keypair with
+// another public key is replaced. The keypair is deleted if the SSHPublicKey
+// was removed from the cluster.
+func (s *Service) ReconcileKeyPair(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	name := keyPairName(clusterName)
+	publicKey := strings.TrimSpace(openStackCluster.Spec.SSHPublicKey)
+	if publicKey == "" {
+		if openStackCluster.Status.SSHKeyName != "" {
+			if err := s.DeleteKeyPair(openStackCluster, openStackCluster.Status.SSHKeyName); err != nil {
+				return err
+			}
+			openStackCluster.Status.SSHKeyName = ""
+		}
+		return nil
+	}
+
+	keyPair, err := keypairs.Get(s.computeClient, name).Extract()
+	if err != nil && !capoerrors.IsNotFound(err) {
+		return fmt.Errorf("error getting keypair %s: %v", name, err)
+	}
+	if err == nil {
+		if strings.TrimSpace(keyPair.PublicKey) == publicKey {
+			openStackCluster.Status.SSHKeyName = name
+			return nil
+		}
+		s.logger.Info("Replacing keypair with changed public key", "name", name)
+		if err := s.DeleteKeyPair(openStackCluster, name); err != nil {
+			return err
+		}
+	}
+
+	if _, err := keypairs.Create(s.computeClient, keypairs.CreateOpts{
+		Name:      name,
+		PublicKey: publicKey,
+	}).Extract(); err != nil {
+		record.Warnf(openStackCluster, "FailedCreateKeyPair", "Failed to create keypair %s: %v", name, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulCreateKeyPair", "Created keypair %s", name)
+	openStackCluster.Status.SSHKeyName = name
+	return nil
+}
+
+// DeleteKeyPair deletes the keypair of the cluster.
+func (s *Service) DeleteKeyPair(openStackCluster *infrav1.OpenStackCluster, name string) error {
+	if err := keypairs.Delete(s.computeClient, name).ExtractErr(); err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		record.Warnf(openStackCluster, "FailedDeleteKeyPair", "Failed to delete keypair %s: %v", name, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulDeleteKeyPair", "Deleted keypair %s", name)
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tags"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/microversion"
+)
+
+// computeMicroversionTags is the minimum compute API microversion which
+// supports the tags of a server.
+const computeMicroversionTags = "2.26"
+
+// metadataTagKeyPrefix prefixes the metadata keys which carry the tags of a
+// server on clouds whose compute API doesn't support tags, e.g. capo-tag-0.
+// The tags are the values, as metadata keys are limited to a few characters.
+const metadataTagKeyPrefix = "capo-tag-"
+
+// instanceTags returns the tags of the server of the machine. They need to be
+// unique or the "apply tags" call will fail.
+func instanceTags(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) []string {
+	machineTags := append(append([]string{}, openStackMachine.Spec.Tags...), openStackCluster.Spec.Tags...)
+	return deduplicate(append(machineTags, openStackCluster.Spec.ComputeTags...))
+}
+
+// ClusterInstanceTags returns the tags which all servers of the machines of the
+// cluster carry.
+func ClusterInstanceTags(openStackCluster *infrav1.OpenStackCluster) []string {
+	return deduplicate(append(append([]string{}, openStackCluster.Spec.Tags...), openStackCluster.Spec.ComputeTags...))
+}
+
+// ReconcileInstanceMetadataAndTags updates the metadata and the tags of an
+// existing server to match the machine, e.g. after the serverMetadata or the
+// tags of the OpenStackMachine were changed. Metadata keys which were removed
+// from the machine are kept on the server, as they may have been set outside
+// of Cluster API.
+func (s *Service) ReconcileInstanceMetadataAndTags(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) error {
+	metadata := servers.MetadataOpts{}
+	for key, value := range instanceMetadata(openStackMachine) {
+		if observed, ok := instance.Metadata[key]; !ok || observed != value {
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
+		s.logger.Info("Updating server metadata", "instance-id", instance.ID, "metadata", metadata)
+		if _, err := servers.UpdateMetadata(s.computeClient, instance.ID, metadata).Extract(); err != nil {
+			record.Warnf(openStackMachine, "FailedUpdateServerMetadata", "Failed to update metadata of server %s with id %s: %v", instance.Name, instance.ID, err)
+			return err
+		}
+		record.Eventf(openStackMachine, "SuccessfulUpdateServerMetadata", "Updated metadata of server %s with id %s", instance.Name, instance.ID)
+	}
+
+	maximum, err := s.maxComputeMicroversion()
+	if err != nil {
+		return err
+	}
+	tagsSupported, err := microversion.AtLeast(maximum, computeMicroversionTags)
+	if err != nil {
+		return err
+	}
+	if !tagsSupported {
+		return s.reconcileMetadataTags(openStackCluster, openStackMachine, instance)
+	}
+
+	client := *s.computeClient
+	client.Microversion = computeMicroversionTags
+	observedTags, err := tags.List(&client, instance.ID).Extract()
+	if err != nil {
+		return fmt.Errorf("error listing tags of server %s: %v", instance.ID, err)
+	}
+	desiredTags := instanceTags(openStackCluster, openStackMachine)
+	if equalTags(observedTags, desiredTags) {
+		return nil
+	}
+	s.logger.Info("Updating server tags", "instance-id", instance.ID, "tags", desiredTags)
+	if _, err := tags.ReplaceAll(&client, instance.ID, tags.ReplaceAllOpts{Tags: desiredTags}).Extract(); err != nil {
+		record.Warnf(openStackMachine, "FailedUpdateServerTags", "Failed to update tags of server %s with id %s: %v", instance.Name, instance.ID, err)
+		return err
+	}
+	record.Eventf(openStackMachine, "SuccessfulUpdateServerTags", "Updated tags of server %s with id %s", instance.Name, instance.ID)
+	return nil
+}
+
+// reconcileMetadataTags updates the metadata which carries the tags of a server
+// on clouds whose compute API doesn't support tags.
+func (s *Service) reconcileMetadataTags(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) error {
+	desired := metadataTags(instanceTags(openStackCluster, openStackMachine))
+	metadata := servers.MetadataOpts{}
+	for key, value := range desired {
+		if observed, ok := instance.Metadata[key]; !ok || observed != value {
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
+		s.logger.Info("Updating server tags in metadata", "instance-id", instance.ID, "metadata", metadata)
+		if _, err := servers.UpdateMetadata(s.computeClient, instance.ID, metadata).Extract(); err != nil {
+			record.Warnf(openStackMachine, "FailedUpdateServerTags", "Failed to update tags of server %s with id %s: %v", instance.Name, instance.ID, err)
+			return err
+		}
+	}
+
+	deleted := false
+	for key := range instance.Metadata {
+		if _, ok := desired[key]; ok || !strings.HasPrefix(key, metadataTagKeyPrefix) {
+			continue
+		}
+		if err := servers.DeleteMetadatum(s.computeClient, instance.ID, key).ExtractErr(); err != nil {
+			record.Warnf(openStackMachine, "FailedUpdateServerTags", "Failed to update tags of server %s with id %s: %v", instance.Name, instance.ID, err)
+			return err
+		}
+		deleted = true
+	}
+	if len(metadata) > 0 || deleted {
+		record.Eventf(openStackMachine, "SuccessfulUpdateServerTags", "Updated tags of server %s with id %s", instance.Name, instance.ID)
+	}
+	return nil
+}
+
+// metadataTags returns the metadata which carries the tags of a server on
+// clouds whose compute API doesn't support tags.
+func metadataTags(tags []string) map[string]string {
+	metadata := make(map[string]string, len(tags))
+	for i, tag := range tags {
+		metadata[fmt.Sprintf("%s%d", metadataTagKeyPrefix, i)] = tag
+	}
+	return metadata
+}
+
+func equalTags(a, b []string) bool {
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
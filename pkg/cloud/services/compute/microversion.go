@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/microversion"
+)
+
+// computeMicroversionTagsOnCreate is the minimum compute API microversion
+// which allows to set tags when creating a server.
+const computeMicroversionTagsOnCreate = "2.52"
+
+// computeFeature is a feature of the compute API which requires a microversion.
+type computeFeature struct {
+	name         string
+	microversion string
+}
+
+// maxComputeMicroversion returns the newest microversion the compute API
+// supports. It is cached like the lookups of images and flavors. An empty
+// microversion means that the API doesn't support microversions at all.
+func (s *Service) maxComputeMicroversion() (string, error) {
+	return s.cachedLookup("compute-microversion", s.computeClient.Endpoint, "", func() (string, error) {
+		var result struct {
+			Version struct {
+				Version string `json:"version"`
+			} `json:"version"`
+		}
+		_, err := s.computeClient.Get(s.computeClient.ServiceURL(), &result, &gophercloud.RequestOpts{OkCodes: []int{200}})
+		if err != nil {
+			return "", fmt.Errorf("error getting the compute API version: %v", err)
+		}
+		return result.Version.Version, nil
+	})
+}
+
+// requireComputeMicroversion returns an error naming the feature if the compute
+// API doesn't support at least the given microversion.
+func (s *Service) requireComputeMicroversion(minimum, feature string) error {
+	maximum, err := s.maxComputeMicroversion()
+	if err != nil {
+		return err
+	}
+	supported, err := microversion.AtLeast(maximum, minimum)
+	if err != nil {
+		return err
+	}
+	if supported {
+		return nil
+	}
+	if maximum == "" {
+		return fmt.Errorf("%s require compute API microversion %s, but the compute API doesn't support microversions", feature, minimum)
+	}
+	return fmt.Errorf("%s require compute API microversion %s, but the compute API only supports up to %s", feature, minimum, maximum)
+}
+
+// computeClientFor returns a compute client pinned to the newest microversion
+// the given features require, after checking that the cloud supports it. The
+// default client is returned if no feature requires a microversion.
+func (s *Service) computeClientFor(features ...computeFeature) (*gophercloud.ServiceClient, error) {
+	pinned := ""
+	for _, feature := range features {
+		if err := s.requireComputeMicroversion(feature.microversion, feature.name); err != nil {
+			return nil, err
+		}
+		newer := pinned == ""
+		if !newer {
+			var err error
+			newer, err = microversion.AtLeast(feature.microversion, pinned)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if newer {
+			pinned = feature.microversion
+		}
+	}
+	if pinned == "" {
+		return s.computeClient, nil
+	}
+	client := *s.computeClient
+	client.Microversion = pinned
+	return &client, nil
+}
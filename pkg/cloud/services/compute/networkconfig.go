@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/textproto"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	cloudConfigHeader = "#cloud-config"
+
+	// networkConfigPath is the netplan file the secondary network interfaces
+	// are configured in.
+	networkConfigPath = "/etc/netplan/60-cluster-api-provider-openstack.yaml"
+
+	// networkConfigMergeType makes cloud-init run our commands before the
+	// ones of the bootstrap provider and keep all other settings of it.
+	networkConfigMergeType = "list(prepend)+dict(no_replace,recurse_list)+str()"
+)
+
+type netplanConfig struct {
+	Network netplanNetwork `json:"network"`
+}
+
+type netplanNetwork struct {
+	Version   int                        `json:"version"`
+	Ethernets map[string]netplanEthernet `json:"ethernets"`
+}
+
+type netplanEthernet struct {
+	Match          netplanMatch          `json:"match"`
+	DHCP4          bool                  `json:"dhcp4,omitempty"`
+	DHCP6          bool                  `json:"dhcp6,omitempty"`
+	DHCP4Overrides *netplanDHCPOverrides `json:"dhcp4-overrides,omitempty"`
+	DHCP6Overrides *netplanDHCPOverrides `json:"dhcp6-overrides,omitempty"`
+	Addresses      []string              `json:"addresses,omitempty"`
+	Nameservers    *netplanNameservers   `json:"nameservers,omitempty"`
+}
+
+type netplanMatch struct {
+	MACAddress string `json:"macaddress"`
+}
+
+type netplanDHCPOverrides struct {
+	UseRoutes bool `json:"use-routes"`
+}
+
+type netplanNameservers struct {
+	Addresses []string `json:"addresses"`
+}
+
+type cloudConfigFile struct {
+	Path        string `json:"path"`
+	Permissions string `json:"permissions"`
+	Content     string `json:"content"`
+}
+
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `json:"write_files"`
+	RunCmd     [][]string        `json:"runcmd"`
+}
+
+// withNetworkConfig returns the user data extended by a cloud-config part
+// which configures the secondary network interfaces of a server with multiple
+// ports. The primary interface is configured by cloud-init from the
+// datasource. Secondary interfaces use DHCP if it is enabled on their subnets
+// and static addresses otherwise, and never provide the default route.
+// The user data is base64 encoded, like the bootstrap data of the machine, and
+// so is the result. User data which is not cloud-config, e.g. Ignition, is
+// returned unchanged.
+func withNetworkConfig(is *Service, userData string, serverPorts []ports.Port) (string, error) {
+	if len(serverPorts) < 2 {
+		return userData, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return "", fmt.Errorf("decode user data: %v", err)
+	}
+	if !strings.HasPrefix(string(decoded), cloudConfigHeader) {
+		return userData, nil
+	}
+
+	config := netplanConfig{
+		Network: netplanNetwork{
+			Version:   2,
+			Ethernets: map[string]netplanEthernet{},
+		},
+	}
+	for i, port := range serverPorts[1:] {
+		ethernet, err := getNetplanEthernet(is, port)
+		if err != nil {
+			return "", err
+		}
+		config.Network.Ethernets[fmt.Sprintf("capo%d", i+1)] = ethernet
+	}
+	netplan, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshal network config: %v", err)
+	}
+
+	part, err := yaml.Marshal(cloudConfig{
+		WriteFiles: []cloudConfigFile{{
+			Path:        networkConfigPath,
+			Permissions: "0600",
+			Content:     string(netplan),
+		}},
+		RunCmd: [][]string{{"netplan", "apply"}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal network cloud-config: %v", err)
+	}
+
+	merged, err := multipartUserData(string(decoded), cloudConfigHeader+"\n"+string(part))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(merged)), nil
+}
+
+func getNetplanEthernet(is *Service, port ports.Port) (netplanEthernet, error) {
+	ethernet := netplanEthernet{
+		Match: netplanMatch{MACAddress: port.MACAddress},
+	}
+	for _, fixedIP := range port.FixedIPs {
+		subnet, err := subnets.Get(is.networkClient, fixedIP.SubnetID).Extract()
+		if err != nil {
+			return ethernet, fmt.Errorf("get subnet %q of port %q: %v", fixedIP.SubnetID, port.ID, err)
+		}
+		isIPv4 := net.ParseIP(fixedIP.IPAddress).To4() != nil
+		switch {
+		case subnet.EnableDHCP && isIPv4:
+			ethernet.DHCP4 = true
+			ethernet.DHCP4Overrides = &netplanDHCPOverrides{UseRoutes: false}
+		case subnet.EnableDHCP:
+			ethernet.DHCP6 = true
+			ethernet.DHCP6Overrides = &netplanDHCPOverrides{UseRoutes: false}
+		default:
+			_, cidr, err := net.ParseCIDR(subnet.CIDR)
+			if err != nil {
+				return ethernet, fmt.Errorf("parse CIDR of subnet %q: %v", subnet.ID, err)
+			}
+			prefix, _ := cidr.Mask.Size()
+			ethernet.Addresses = append(ethernet.Addresses, fmt.Sprintf("%s/%d", fixedIP.IPAddress, prefix))
+			if len(subnet.DNSNameservers) > 0 {
+				if ethernet.Nameservers == nil {
+					ethernet.Nameservers = &netplanNameservers{}
+				}
+				ethernet.Nameservers.Addresses = append(ethernet.Nameservers.Addresses, subnet.DNSNameservers...)
+			}
+		}
+	}
+	return ethernet, nil
+}
+
+// hasStaticAddresses reports whether any of the ports has a fixed IP on a
+// subnet without DHCP. Such a server can't reach the metadata service before
+// its network is configured, so it needs the network_data.json which Nova
+// writes to the config drive.
+func hasStaticAddresses(is *Service, serverPorts []ports.Port) (bool, error) {
+	for _, port := range serverPorts {
+		for _, fixedIP := range port.FixedIPs {
+			subnet, err := subnets.Get(is.networkClient, fixedIP.SubnetID).Extract()
+			if err != nil {
+				return false, fmt.Errorf("get subnet %q of port %q: %v", fixedIP.SubnetID, port.ID, err)
+			}
+			if !subnet.EnableDHCP {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// multipartUserData combines the user data of the bootstrap provider and the
+// given cloud-config part into a multipart MIME message as understood by
+// cloud-init.
+func multipartUserData(userData, part string) (string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", w.Boundary())
+
+	for _, p := range []struct {
+		header textproto.MIMEHeader
+		body   string
+	}{
+		{
+			header: textproto.MIMEHeader{"Content-Type": {"text/cloud-config; charset=\"us-ascii\""}},
+			body:   userData,
+		},
+		{
+			header: textproto.MIMEHeader{
+				"Content-Type": {"text/cloud-config; charset=\"us-ascii\""},
+				"Merge-Type":   {networkConfigMergeType},
+			},
+			body: part,
+		},
+	} {
+		pw, err := w.CreatePart(p.header)
+		if err != nil {
+			return "", fmt.Errorf("create user data part: %v", err)
+		}
+		if _, err := pw.Write([]byte(p.body)); err != nil {
+			return "", fmt.Errorf("write user data part: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("close user data: %v", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// ReconcileInstanceImage rebuilds the server of the machine in place if it was
+// created from another image than the one of the machine, e.g. after the image
+// of a bare metal machine was changed. It waits until the server is active
+// again and returns it. Servers which boot from a volume are left untouched.
+func (s *Service) ReconcileInstanceImage(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) (*infrav1.Instance, error) {
+	if instance.ImageUUID == "" {
+		return instance, nil
+	}
+	imageID, err := resolveImageID(s, openStackMachine.Spec.ImageUUID, openStackMachine.Spec.ImageFilter, openStackMachine.Spec.Image)
+	if err != nil {
+		return nil, err
+	}
+	if imageID == instance.ImageUUID {
+		return instance, nil
+	}
+
+	s.logger.Info("Rebuilding server with new image", "instance-id", instance.ID, "image-id", imageID)
+	// A locked instance can't be rebuilt.
+	if err := lockunlock.Unlock(s.computeClient, instance.ID).ExtractErr(); err != nil {
+		return nil, fmt.Errorf("error unlocking the instance %s: %v", instance.ID, err)
+	}
+	_, err = servers.Rebuild(s.computeClient, instance.ID, servers.RebuildOpts{
+		ImageRef: imageID,
+		Name:     instance.Name,
+	}).Extract()
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedRebuildServer", "Failed to rebuild server %s with id %s: %v", instance.Name, instance.ID, err)
+		return nil, err
+	}
+
+	var rebuilt *infrav1.Instance
+	err = util.PollImmediate(RetryIntervalInstanceStatus, instanceCreateTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
+		rebuilt, err = s.GetInstance(instance.ID)
+		if err != nil {
+			return false, err
+		}
+		if rebuilt == nil {
+			return false, fmt.Errorf("instance %s disappeared while it was rebuilt", instance.ID)
+		}
+		if rebuilt.State == infrav1.InstanceStateError {
+			if fault := s.InstanceFault(instance.ID); fault != "" {
+				return false, fmt.Errorf("instance is in state %s: %s", rebuilt.State, fault)
+			}
+			return false, fmt.Errorf("instance is in state %s", rebuilt.State)
+		}
+		return rebuilt.State == infrav1.InstanceStateActive, nil
+	})
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedRebuildServer", "Failed to rebuild server %s with id %s: %v", instance.Name, instance.ID, err)
+		return nil, fmt.Errorf("error rebuilding Openstack instance %s, %v", instance.ID, err)
+	}
+	record.Eventf(openStackMachine, "SuccessfulRebuildServer", "Rebuilt server %s with id %s from image %s", instance.Name, instance.ID, imageID)
+
+	if openStackCluster.Spec.LockInstances {
+		lockInstance(s, openStackMachine, rebuilt)
+	}
+	return rebuilt, nil
+}
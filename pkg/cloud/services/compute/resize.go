@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// ReconcileInstanceFlavor resizes the server of the machine if it runs with
+// another flavor than the one of the machine. The resize is confirmed as soon
+// as the server waits for it, so the old flavor can't be restored afterwards.
+// A server which already waits for the confirmation, e.g. because the
+// controller was restarted during the resize, is confirmed as well.
+func (s *Service) ReconcileInstanceFlavor(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) (*infrav1.Instance, error) {
+	if instance.State != infrav1.InstanceStateVerifyResize {
+		flavorID := openStackMachine.Spec.FlavorID
+		if flavorID == "" {
+			var err error
+			flavorID, err = getFlavorID(s, openStackMachine.Spec.Flavor)
+			if err != nil {
+				return nil, fmt.Errorf("error getting flavor id from flavor name %s: %v", openStackMachine.Spec.Flavor, err)
+			}
+		}
+		// The flavor of the server is unknown if the compute API only returns
+		// its name, so it is never resized.
+		if instance.FlavorID == "" || flavorID == instance.FlavorID {
+			return instance, nil
+		}
+
+		s.logger.Info("Resizing server to new flavor", "instance-id", instance.ID, "flavor-id", flavorID)
+		// A locked instance can't be resized.
+		if err := lockunlock.Unlock(s.computeClient, instance.ID).ExtractErr(); err != nil {
+			return nil, fmt.Errorf("error unlocking the instance %s: %v", instance.ID, err)
+		}
+		if err := servers.Resize(s.computeClient, instance.ID, servers.ResizeOpts{FlavorRef: flavorID}).ExtractErr(); err != nil {
+			record.Warnf(openStackMachine, "FailedResizeServer", "Failed to resize server %s with id %s: %v", instance.Name, instance.ID, err)
+			return nil, err
+		}
+
+		// Clouds may be configured to confirm resizes automatically, then the
+		// server becomes active right away.
+		resized, err := s.waitForInstanceState(openStackCluster, instance.ID, infrav1.InstanceStateVerifyResize, infrav1.InstanceStateActive)
+		if err != nil {
+			record.Warnf(openStackMachine, "FailedResizeServer", "Failed to resize server %s with id %s: %v", instance.Name, instance.ID, err)
+			return nil, fmt.Errorf("error resizing Openstack instance %s, %v", instance.ID, err)
+		}
+		instance = resized
+	}
+
+	if instance.State == infrav1.InstanceStateVerifyResize {
+		if err := servers.ConfirmResize(s.computeClient, instance.ID).ExtractErr(); err != nil {
+			record.Warnf(openStackMachine, "FailedResizeServer", "Failed to confirm resize of server %s with id %s: %v", instance.Name, instance.ID, err)
+			return nil, err
+		}
+		confirmed, err := s.waitForInstanceState(openStackCluster, instance.ID, infrav1.InstanceStateActive)
+		if err != nil {
+			record.Warnf(openStackMachine, "FailedResizeServer", "Failed to confirm resize of server %s with id %s: %v", instance.Name, instance.ID, err)
+			return nil, fmt.Errorf("error confirming resize of Openstack instance %s, %v", instance.ID, err)
+		}
+		instance = confirmed
+	}
+	record.Eventf(openStackMachine, "SuccessfulResizeServer", "Resized server %s with id %s to flavor %s", instance.Name, instance.ID, instance.FlavorID)
+
+	if openStackCluster.Spec.LockInstances {
+		lockInstance(s, openStackMachine, instance)
+	}
+	return instance, nil
+}
+
+// waitForInstanceState waits until the instance is in one of the given states
+// and returns it. It fails as soon as the instance is in state ERROR.
+func (s *Service) waitForInstanceState(openStackCluster *infrav1.OpenStackCluster, instanceID string, states ...infrav1.InstanceState) (*infrav1.Instance, error) {
+	var instance *infrav1.Instance
+	err := util.PollImmediate(RetryIntervalInstanceStatus, instanceCreateTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
+		var err error
+		instance, err = s.GetInstance(instanceID)
+		if err != nil {
+			return false, err
+		}
+		if instance == nil {
+			return false, fmt.Errorf("instance %s disappeared", instanceID)
+		}
+		if instance.State == infrav1.InstanceStateError {
+			if fault := s.InstanceFault(instanceID); fault != "" {
+				return false, fmt.Errorf("instance is in state %s: %s", instance.State, fault)
+			}
+			return false, fmt.Errorf("instance is in state %s", instance.State)
+		}
+		for _, state := range states {
+			if instance.State == state {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return instance, err
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// machineSecurityGroups returns the IDs of the security groups of the ports of
+// the machine, including the managed security group of its role.
+func machineSecurityGroups(is *Service, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) ([]string, error) {
+	securityGroups, err := getSecurityGroups(is, openStackMachine.Spec.SecurityGroups)
+	if err != nil {
+		return nil, err
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups {
+		if util.IsControlPlaneMachine(machine) {
+			securityGroups = append(securityGroups, openStackCluster.Status.ControlPlaneSecurityGroup.ID)
+		} else {
+			securityGroups = append(securityGroups, openStackCluster.Status.WorkerSecurityGroup.ID)
+		}
+	}
+	return securityGroups, nil
+}
+
+// ReconcileInstanceSecurityGroups updates the security groups of the ports of
+// an existing server to match the machine, e.g. after a security group was
+// added to the OpenStackMachine. Only the ports created for the machine are
+// updated, existing ports and ports without port security are left untouched.
+// Ports defined with their own security groups get these instead of the ones
+// of the machine.
+func (s *Service) ReconcileInstanceSecurityGroups(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance) error {
+	machineGroups, err := machineSecurityGroups(s, openStackCluster, machine, openStackMachine)
+	if err != nil {
+		return err
+	}
+
+	// The ports defined in Ports are recognized by their name.
+	portOptsByName := map[string]*infrav1.PortOpts{}
+	for i := range openStackMachine.Spec.Ports {
+		portOpts := &openStackMachine.Spec.Ports[i]
+		portOptsByName[portName(instance.Name, portOpts, i)] = portOpts
+	}
+
+	allPages, err := ports.List(s.networkClient, ports.ListOpts{
+		DeviceID: instance.ID,
+		Tags:     machinePortTag(openStackMachine),
+	}).AllPages()
+	if err != nil {
+		return fmt.Errorf("error listing ports of instance %s: %v", instance.ID, err)
+	}
+	var serverPorts []struct {
+		ports.Port
+		portsecurity.PortSecurityExt
+	}
+	if err := ports.ExtractPortsInto(allPages, &serverPorts); err != nil {
+		return fmt.Errorf("error listing ports of instance %s: %v", instance.ID, err)
+	}
+
+	for _, port := range serverPorts {
+		if !port.PortSecurityEnabled {
+			continue
+		}
+		desired := machineGroups
+		if portOpts, ok := portOptsByName[port.Name]; ok && portOpts.SecurityGroups != nil {
+			desired, err = getSecurityGroups(s, *portOpts.SecurityGroups)
+			if err != nil {
+				return err
+			}
+		}
+		if equalSecurityGroups(desired, port.SecurityGroups) {
+			continue
+		}
+
+		s.logger.Info("Updating security groups of port", "port-id", port.ID, "securityGroups", desired)
+		updated := append([]string{}, desired...)
+		if _, err := ports.Update(s.networkClient, port.ID, ports.UpdateOpts{SecurityGroups: &updated}).Extract(); err != nil {
+			record.Warnf(openStackMachine, "FailedUpdatePortSecurityGroups", "Failed to update security groups of port %s with id %s: %v", port.Name, port.ID, err)
+			return err
+		}
+		record.Eventf(openStackMachine, "SuccessfulUpdatePortSecurityGroups", "Updated security groups of port %s with id %s from %v to %v", port.Name, port.ID, port.SecurityGroups, desired)
+	}
+	return nil
+}
+
+// equalSecurityGroups returns whether both lists contain the same security
+// groups, regardless of their order and duplicates.
+func equalSecurityGroups(a, b []string) bool {
+	a = deduplicate(append([]string{}, a...))
+	b = deduplicate(append([]string{}, b...))
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
@@ -30,6 +30,7 @@ import (
 type Service struct {
 	provider       *gophercloud.ProviderClient
 	projectID      string
+	regionName     string
 	computeClient  *gophercloud.ServiceClient
 	identityClient *gophercloud.ServiceClient
 	networkClient  *gophercloud.ServiceClient
@@ -85,6 +86,7 @@ func NewService(client *gophercloud.ProviderClient, clientOpts *clientconfig.Cli
 	return &Service{
 		provider:       client,
 		projectID:      projectID,
+		regionName:     clientOpts.RegionName,
 		identityClient: identityClient,
 		computeClient:  computeClient,
 		networkClient:  networkingClient,
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/shelveunshelve"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	// shelvedClusterMetadataKey marks a server which was shelved instead of
+	// deleted together with its machine. The value is the name of the cluster.
+	shelvedClusterMetadataKey = "capo-shelved-cluster"
+	// shelvedUntilMetadataKey is the time in RFC 3339 format after which the
+	// shelved server is deleted.
+	shelvedUntilMetadataKey = "capo-shelved-until"
+)
+
+// InstanceShelve shelves the server of the machine instead of deleting it, so
+// that its root disk is kept. Existing ports of the machine are detached, so
+// that a machine which replaces it can use them.
+func (s *Service) InstanceShelve(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instance *infrav1.Instance, clusterName string) error {
+	if isShelved(instance.State) {
+		return nil
+	}
+
+	metadata := servers.MetadataOpts{shelvedClusterMetadataKey: clusterName}
+	if retention := openStackMachine.Spec.ShelveOnDelete.Retention; retention != nil {
+		metadata[shelvedUntilMetadataKey] = time.Now().Add(retention.Duration).UTC().Format(time.RFC3339)
+	}
+	if _, err := servers.UpdateMetadata(s.computeClient, instance.ID, metadata).Extract(); err != nil {
+		return fmt.Errorf("error marking the instance %s as shelved: %v", instance.ID, err)
+	}
+
+	for _, portID := range existingPortIDs(openStackMachine.Spec.Ports) {
+		err := attachinterfaces.Delete(s.computeClient, instance.ID, portID).ExtractErr()
+		if err != nil && !capoerrors.IsNotFound(err) {
+			return fmt.Errorf("error detaching the port %s from the instance %s: %v", portID, instance.ID, err)
+		}
+	}
+
+	// A locked instance can't be shelved.
+	if err := lockunlock.Unlock(s.computeClient, instance.ID).ExtractErr(); err != nil {
+		return fmt.Errorf("error unlocking the instance %s: %v", instance.ID, err)
+	}
+	if err := shelveunshelve.Shelve(s.computeClient, instance.ID).ExtractErr(); err != nil {
+		record.Warnf(openStackMachine, "FailedShelveServer", "Failed to shelve server %s with id %s: %v", instance.Name, instance.ID, err)
+		return fmt.Errorf("error shelving the instance %s: %v", instance.ID, err)
+	}
+
+	err := util.PollImmediate(RetryIntervalInstanceStatus, instanceDeleteTimeout(openStackCluster.Spec.Timeouts), func() (bool, error) {
+		current, err := s.GetInstance(instance.ID)
+		if err != nil {
+			return false, err
+		}
+		return current == nil || isShelved(current.State), nil
+	})
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedShelveServer", "Failed to shelve server %s with id %s: %v", instance.Name, instance.ID, err)
+		return fmt.Errorf("error waiting for the instance %s to be shelved: %v", instance.ID, err)
+	}
+
+	record.Eventf(openStackMachine, "SuccessfulShelveServer", "Shelved server %s with id %s", instance.Name, instance.ID)
+	return nil
+}
+
+// DeleteShelvedInstances deletes the servers of the cluster which were shelved
+// instead of deleted together with their machine. Unless all is set, only the
+// servers whose retention has passed are deleted.
+func (s *Service) DeleteShelvedInstances(openStackCluster *infrav1.OpenStackCluster, clusterName string, all bool) error {
+	for _, state := range []infrav1.InstanceState{infrav1.InstanceStateShelved, infrav1.InstanceStateShelvedOffloaded} {
+		listOpts := servers.ListOpts{
+			Status: string(state),
+			Tags:   strings.Join(ClusterInstanceTags(openStackCluster), ","),
+		}
+		allPages, err := servers.List(s.computeClient, listOpts).AllPages()
+		if err != nil {
+			return fmt.Errorf("error listing shelved servers: %v", err)
+		}
+		serverList, err := servers.ExtractServers(allPages)
+		if err != nil {
+			return fmt.Errorf("error listing shelved servers: %v", err)
+		}
+		for _, server := range serverList {
+			if server.Metadata[shelvedClusterMetadataKey] != clusterName {
+				continue
+			}
+			if !all && !shelveExpired(server.Metadata) {
+				continue
+			}
+			if err := deleteInstance(s, server.ID, nil, openStackCluster.Spec.Timeouts); err != nil {
+				record.Warnf(openStackCluster, "FailedDeleteServer", "Failed to delete shelved server %s with id %s: %v", server.Name, server.ID, err)
+				return err
+			}
+			record.Eventf(openStackCluster, "SuccessfulDeleteServer", "Deleted shelved server %s with id %s", server.Name, server.ID)
+		}
+	}
+	return nil
+}
+
+// shelveExpired returns whether the retention of a shelved server has passed.
+// Servers without a retention are kept.
+func shelveExpired(metadata map[string]string) bool {
+	until, ok := metadata[shelvedUntilMetadataKey]
+	if !ok {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return false
+	}
+	return time.Now().After(t)
+}
+
+func isShelved(state infrav1.InstanceState) bool {
+	return state == infrav1.InstanceStateShelved || state == infrav1.InstanceStateShelvedOffloaded
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"time"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+)
+
+// instanceCreateTimeout returns the time to wait for a created server to
+// become active. Without a timeout in the cluster spec, the timeout in minutes
+// is read from the environment of the controller.
+func instanceCreateTimeout(timeouts *infrav1.Timeouts) time.Duration {
+	if timeouts != nil && timeouts.InstanceCreate != nil {
+		return timeouts.InstanceCreate.Duration
+	}
+	return getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", TimeoutInstanceCreate) * time.Minute
+}
+
+// instanceDeleteTimeout returns the time to wait for a server to be deleted.
+func instanceDeleteTimeout(timeouts *infrav1.Timeouts) time.Duration {
+	if timeouts != nil && timeouts.InstanceDelete != nil {
+		return timeouts.InstanceDelete.Duration
+	}
+	return TimeoutInstanceDelete
+}
+
+// instanceStopTimeout returns the time to wait for a server to shut off before
+// it is deleted.
+func instanceStopTimeout(timeouts *infrav1.Timeouts) time.Duration {
+	if timeouts != nil && timeouts.InstanceStop != nil {
+		return timeouts.InstanceStop.Duration
+	}
+	return TimeoutInstanceStop
+}
+
+// portDeleteTimeout returns the time to retry the deletion of a port.
+func portDeleteTimeout(timeouts *infrav1.Timeouts) time.Duration {
+	if timeouts != nil && timeouts.PortDelete != nil {
+		return timeouts.PortDelete.Duration
+	}
+	return TimeoutPortDelete
+}
+
+// trunkDeleteTimeout returns the time to retry the deletion of a trunk.
+func trunkDeleteTimeout(timeouts *infrav1.Timeouts) time.Duration {
+	if timeouts != nil && timeouts.TrunkDelete != nil {
+		return timeouts.TrunkDelete.Duration
+	}
+	return TimeoutTrunkDelete
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+)
+
+// maxUserDataSize is the maximum size of the base64 encoded user data
+// accepted by Nova.
+const maxUserDataSize = 65535
+
+// compressUserData gzips the user data if it exceeds the limit of Nova.
+// cloud-init detects and decompresses gzipped user data by itself. The user
+// data is base64 encoded, like the bootstrap data of the machine, and so is
+// the result.
+func compressUserData(userData string) (string, error) {
+	if len(userData) <= maxUserDataSize {
+		return userData, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return "", fmt.Errorf("decode user data: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(decoded); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	compressed := base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(compressed) > maxUserDataSize {
+		return "", fmt.Errorf("user data of %d bytes exceeds the limit of %d bytes of Nova even when compressed to %d base64 encoded bytes", len(decoded), maxUserDataSize, len(compressed))
+	}
+	return compressed, nil
+}
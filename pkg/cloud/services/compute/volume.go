@@ -0,0 +1,260 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/quotasets"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"sigs.k8s.io/cluster-api/util"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+)
+
+const (
+	volumeStatusAvailable      = "available"
+	volumeStatusError          = "error"
+	volumeStatusErrorDetaching = "error_detaching"
+
+	// gibibyte is the unit of the sizes of volumes.
+	gibibyte = 1024 * 1024 * 1024
+)
+
+func (is *Service) getVolumeClient() (*gophercloud.ServiceClient, error) {
+	volumeClient, err := openstack.NewBlockStorageV3(is.provider, gophercloud.EndpointOpts{
+		Region: is.regionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create volume service client: %v", err)
+	}
+	return volumeClient, nil
+}
+
+// rootVolumeName returns the name of the root volume of the instance, if it is
+// created with Cinder.
+func rootVolumeName(instanceName string) string {
+	return fmt.Sprintf("%s-root", instanceName)
+}
+
+// additionalVolumeName returns the name of the volume of an additional block
+// device of the instance.
+func additionalVolumeName(instanceName string, blockDevice *infrav1.AdditionalBlockDevice) string {
+	return fmt.Sprintf("%s-%s", instanceName, blockDevice.Name)
+}
+
+// validateVolumes checks the volumes of the instance before any of its resources
+// is created: the image of the root volume has to fit into it, and the volumes
+// have to fit into the Cinder quota of the project. Otherwise the server only
+// goes into error after it has been created, with a fault which doesn't tell
+// the reason.
+func (is *Service) validateVolumes(i *infrav1.Instance, additionalBlockDevices []infrav1.AdditionalBlockDevice) error {
+	createRootVolume := i.RootVolume != nil && i.RootVolume.Size != 0 && !bootsFromExistingVolume(i.RootVolume)
+	if !createRootVolume && len(additionalBlockDevices) == 0 {
+		return nil
+	}
+
+	volumeCount, volumeSize := 0, 0
+	if createRootVolume {
+		if i.RootVolume.SourceType == string(bootfromvolume.SourceImage) && i.RootVolume.SourceUUID != "" {
+			image, err := images.Get(is.imagesClient, i.RootVolume.SourceUUID).Extract()
+			if err != nil {
+				return fmt.Errorf("error getting image %s of the root volume: %v", i.RootVolume.SourceUUID, err)
+			}
+			if err := imageFitsVolume(image, i.RootVolume.Size); err != nil {
+				return err
+			}
+		}
+		volumeCount++
+		volumeSize += i.RootVolume.Size
+	}
+	for idx := range additionalBlockDevices {
+		volumeCount++
+		volumeSize += additionalBlockDevices[idx].Size
+	}
+
+	volumeClient, err := is.getVolumeClient()
+	if err != nil {
+		return err
+	}
+	usage, err := quotasets.GetUsage(volumeClient, is.projectID).Extract()
+	if err != nil {
+		// The policy of the cloud may not allow to read the quota usage, which
+		// must not prevent the creation of the instance.
+		is.logger.Info("Skipping the check of the volume quota", "error", err.Error())
+		return nil
+	}
+	if !fitsQuota(usage.Volumes, volumeCount) {
+		return fmt.Errorf("volume quota exceeded: %d volumes required, %d of %d in use", volumeCount, usage.Volumes.InUse+usage.Volumes.Reserved, usage.Volumes.Limit)
+	}
+	if !fitsQuota(usage.Gigabytes, volumeSize) {
+		return fmt.Errorf("volume quota exceeded: %d GiB required, %d of %d GiB in use", volumeSize, usage.Gigabytes.InUse+usage.Gigabytes.Reserved, usage.Gigabytes.Limit)
+	}
+	return nil
+}
+
+// imageFitsVolume returns an error if the image requires a larger volume than
+// the given size in GiB.
+func imageFitsVolume(image *images.Image, size int) error {
+	if image.MinDiskGigabytes > size {
+		return fmt.Errorf("root volume of %d GiB is smaller than the minimum disk size %d GiB of image %s", size, image.MinDiskGigabytes, image.ID)
+	}
+	if image.VirtualSize > int64(size)*gibibyte {
+		return fmt.Errorf("root volume of %d GiB is smaller than the virtual size %d bytes of image %s", size, image.VirtualSize, image.ID)
+	}
+	return nil
+}
+
+// fitsQuota returns whether the quota allows to allocate the given amount. A
+// negative limit means unlimited.
+func fitsQuota(usage quotasets.QuotaUsage, amount int) bool {
+	if usage.Limit < 0 {
+		return true
+	}
+	return usage.InUse+usage.Reserved+amount <= usage.Limit
+}
+
+// getOrCreateVolume returns the volume with the name of the create options, or
+// creates it if it doesn't exist yet, and waits until it is available.
+func getOrCreateVolume(volumeClient *gophercloud.ServiceClient, createOpts volumes.CreateOpts) (*volumes.Volume, error) {
+	name := createOpts.Name
+	allPages, err := volumes.List(volumeClient, volumes.ListOpts{Name: name}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("searching for existing volume %s: %v", name, err)
+	}
+	volumeList, err := volumes.ExtractVolumes(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("searching for existing volume %s: %v", name, err)
+	}
+
+	var volume *volumes.Volume
+	switch len(volumeList) {
+	case 0:
+		volume, err = volumes.Create(volumeClient, createOpts).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("error creating volume %s: %v", name, err)
+		}
+	case 1:
+		volume = &volumeList[0]
+	default:
+		return nil, fmt.Errorf("found %d volumes with name %s", len(volumeList), name)
+	}
+
+	err = util.PollImmediate(RetryIntervalVolumeStatus, TimeoutVolumeCreate, func() (bool, error) {
+		volume, err = volumes.Get(volumeClient, volume.ID).Extract()
+		if err != nil {
+			return false, err
+		}
+		switch volume.Status {
+		case volumeStatusAvailable:
+			return true, nil
+		case volumeStatusError:
+			return false, fmt.Errorf("volume %s is in status %s", volume.ID, volume.Status)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for volume %s to become available: %v", name, err)
+	}
+	return volume, nil
+}
+
+// deleteVolumes deletes volumes which were created for an instance which could
+// not be created.
+func deleteVolumes(volumeClient *gophercloud.ServiceClient, volumeIDs []string) error {
+	for _, volumeID := range volumeIDs {
+		if err := volumes.Delete(volumeClient, volumeID, volumes.DeleteOpts{}).ExtractErr(); err != nil {
+			return fmt.Errorf("error deleting volume %s: %v", volumeID, err)
+		}
+	}
+	return nil
+}
+
+// detachVolumes detaches the volumes of the server before it is deleted and
+// waits until they are available, so that no volume is left in-use when the
+// deletion of the server fails half-way. Bootable volumes are left attached as
+// they may be the root disk. The additional block devices of the machine are
+// deleted afterwards, unless they are preserved, other volumes like the ones
+// of the Cinder CSI driver are kept.
+func (s *Service) detachVolumes(openStackMachine *infrav1.OpenStackMachine, serverID string) error {
+	allPages, err := volumeattach.List(s.computeClient, serverID).AllPages()
+	if err != nil {
+		return fmt.Errorf("error listing volume attachments of server %s: %v", serverID, err)
+	}
+	attachments, err := volumeattach.ExtractVolumeAttachments(allPages)
+	if err != nil {
+		return fmt.Errorf("error listing volume attachments of server %s: %v", serverID, err)
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	volumeClient, err := s.getVolumeClient()
+	if err != nil {
+		return err
+	}
+
+	owned := map[string]bool{}
+	for idx := range openStackMachine.Spec.AdditionalBlockDevices {
+		blockDevice := &openStackMachine.Spec.AdditionalBlockDevices[idx]
+		owned[additionalVolumeName(openStackMachine.Name, blockDevice)] = !blockDevice.PreserveOnDelete
+	}
+
+	for _, attachment := range attachments {
+		volume, err := volumes.Get(volumeClient, attachment.VolumeID).Extract()
+		if err != nil {
+			return fmt.Errorf("error getting volume %s: %v", attachment.VolumeID, err)
+		}
+		deleteVolume, isOwned := owned[volume.Name]
+		if volume.Bootable == "true" && !isOwned {
+			continue
+		}
+
+		s.logger.Info("Detaching volume", "volume-id", volume.ID, "server-id", serverID)
+		if err := volumeattach.Delete(s.computeClient, serverID, attachment.ID).ExtractErr(); err != nil {
+			return fmt.Errorf("error detaching volume %s: %v", volume.ID, err)
+		}
+		err = util.PollImmediate(RetryIntervalVolumeStatus, TimeoutVolumeDetach, func() (bool, error) {
+			volume, err = volumes.Get(volumeClient, volume.ID).Extract()
+			if err != nil {
+				return false, err
+			}
+			switch volume.Status {
+			case volumeStatusAvailable:
+				return true, nil
+			case volumeStatusErrorDetaching:
+				return false, fmt.Errorf("volume %s is in status %s", volume.ID, volume.Status)
+			}
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("error waiting for volume %s to be detached: %v", volume.ID, err)
+		}
+
+		if deleteVolume {
+			if err := volumes.Delete(volumeClient, volume.ID, volumes.DeleteOpts{}).ExtractErr(); err != nil {
+				return fmt.Errorf("error deleting volume %s: %v", volume.ID, err)
+			}
+		}
+	}
+	return nil
+}
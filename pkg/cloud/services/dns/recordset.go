@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+const (
+	recordTypeA    = "A"
+	recordTypeAAAA = "AAAA"
+	recordTypePTR  = "PTR"
+)
+
+// ReconcileInstanceRecords makes sure that the A and AAAA records of the machine
+// contain exactly the given addresses, and that a PTR record exists for each
+// address contained in one of the reverse zones.
+func (s *Service) ReconcileInstanceRecords(openStackMachine *infrav1.OpenStackMachine, config *infrav1.NodeDNSRecords, addresses []string) error {
+	fqdn := recordName(openStackMachine, config)
+
+	zoneID, err := s.getZoneID(config.Zone)
+	if err != nil {
+		return err
+	}
+
+	forward := map[string][]string{recordTypeA: {}, recordTypeAAAA: {}}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return fmt.Errorf("invalid address %q of machine %s", address, openStackMachine.Name)
+		}
+		if ip.To4() != nil {
+			forward[recordTypeA] = append(forward[recordTypeA], address)
+		} else {
+			forward[recordTypeAAAA] = append(forward[recordTypeAAAA], address)
+		}
+	}
+	for _, recordType := range []string{recordTypeA, recordTypeAAAA} {
+		if err := s.reconcileRecordSet(openStackMachine, zoneID, fqdn, recordType, forward[recordType], config.TTL); err != nil {
+			return err
+		}
+	}
+
+	for _, reverseZone := range config.ReverseZones {
+		reverseZoneID, err := s.getZoneID(reverseZone)
+		if err != nil {
+			return err
+		}
+
+		desired := map[string]bool{}
+		for _, address := range addresses {
+			name := reverseName(net.ParseIP(address))
+			if strings.HasSuffix(name, "."+reverseZone) {
+				desired[name] = true
+			}
+		}
+
+		// Remove the PTR records of addresses the machine no longer has, e.g. a
+		// floating IP which has been disassociated.
+		existing, err := s.listRecordSets(reverseZoneID, recordsets.ListOpts{Type: recordTypePTR, Data: fqdn})
+		if err != nil {
+			return err
+		}
+		for _, rs := range existing {
+			if !desired[rs.Name] {
+				if err := s.deleteRecordSet(openStackMachine, rs); err != nil {
+					return err
+				}
+			}
+		}
+
+		for name := range desired {
+			if err := s.reconcileRecordSet(openStackMachine, reverseZoneID, name, recordTypePTR, []string{fqdn}, config.TTL); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteInstanceRecords deletes the A, AAAA and PTR records of the machine.
+func (s *Service) DeleteInstanceRecords(openStackMachine *infrav1.OpenStackMachine, config *infrav1.NodeDNSRecords) error {
+	fqdn := recordName(openStackMachine, config)
+
+	zoneID, err := s.getZoneID(config.Zone)
+	if err != nil {
+		return err
+	}
+	for _, recordType := range []string{recordTypeA, recordTypeAAAA} {
+		existing, err := s.listRecordSets(zoneID, recordsets.ListOpts{Name: fqdn, Type: recordType})
+		if err != nil {
+			return err
+		}
+		for _, rs := range existing {
+			if err := s.deleteRecordSet(openStackMachine, rs); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, reverseZone := range config.ReverseZones {
+		reverseZoneID, err := s.getZoneID(reverseZone)
+		if err != nil {
+			return err
+		}
+		existing, err := s.listRecordSets(reverseZoneID, recordsets.ListOpts{Type: recordTypePTR, Data: fqdn})
+		if err != nil {
+			return err
+		}
+		for _, rs := range existing {
+			if err := s.deleteRecordSet(openStackMachine, rs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// reconcileRecordSet creates, updates or deletes the record set with the given
+// name and type, so that it contains exactly the given records.
+func (s *Service) reconcileRecordSet(openStackMachine *infrav1.OpenStackMachine, zoneID, name, recordType string, records []string, ttl int) error {
+	existing, err := s.listRecordSets(zoneID, recordsets.ListOpts{Name: name, Type: recordType})
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		for _, rs := range existing {
+			if err := s.deleteRecordSet(openStackMachine, rs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(existing) == 0 {
+		createOpts := recordsets.CreateOpts{
+			Name:    name,
+			Type:    recordType,
+			TTL:     ttl,
+			Records: records,
+		}
+		rs, err := recordsets.Create(s.client, zoneID, createOpts).Extract()
+		if err != nil {
+			record.Warnf(openStackMachine, "FailedCreateRecordSet", "Failed to create %s record %s: %v", recordType, name, err)
+			return err
+		}
+		record.Eventf(openStackMachine, "SuccessfulCreateRecordSet", "Created %s record %s with id %s", recordType, name, rs.ID)
+		return nil
+	}
+
+	rs := existing[0]
+	if equalRecords(rs.Records, records) {
+		return nil
+	}
+	s.logger.Info("Updating record set", "name", name, "type", recordType, "records", records)
+	_, err = recordsets.Update(s.client, zoneID, rs.ID, recordsets.UpdateOpts{Records: records}).Extract()
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedUpdateRecordSet", "Failed to update %s record %s: %v", recordType, name, err)
+		return err
+	}
+	record.Eventf(openStackMachine, "SuccessfulUpdateRecordSet", "Updated %s record %s with id %s", recordType, name, rs.ID)
+	return nil
+}
+
+func (s *Service) deleteRecordSet(openStackMachine *infrav1.OpenStackMachine, rs recordsets.RecordSet) error {
+	if err := recordsets.Delete(s.client, rs.ZoneID, rs.ID).ExtractErr(); err != nil {
+		record.Warnf(openStackMachine, "FailedDeleteRecordSet", "Failed to delete %s record %s with id %s: %v", rs.Type, rs.Name, rs.ID, err)
+		return err
+	}
+	record.Eventf(openStackMachine, "SuccessfulDeleteRecordSet", "Deleted %s record %s with id %s", rs.Type, rs.Name, rs.ID)
+	return nil
+}
+
+func (s *Service) listRecordSets(zoneID string, listOpts recordsets.ListOpts) ([]recordsets.RecordSet, error) {
+	allPages, err := recordsets.ListByZone(s.client, zoneID, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return recordsets.ExtractRecordSets(allPages)
+}
+
+func (s *Service) getZoneID(name string) (string, error) {
+	allPages, err := zones.List(s.client, zones.ListOpts{Name: name}).AllPages()
+	if err != nil {
+		return "", err
+	}
+	zoneList, err := zones.ExtractZones(allPages)
+	if err != nil {
+		return "", err
+	}
+	switch len(zoneList) {
+	case 0:
+		return "", fmt.Errorf("no DNS zone with name %q found", name)
+	case 1:
+		return zoneList[0].ID, nil
+	}
+	return "", fmt.Errorf("found %d DNS zones with name %q", len(zoneList), name)
+}
+
+func recordName(openStackMachine *infrav1.OpenStackMachine, config *infrav1.NodeDNSRecords) string {
+	return fmt.Sprintf("%s.%s", openStackMachine.Name, config.Zone)
+}
+
+// reverseName returns the name of the PTR record of the address, e.g.
+// 4.3.2.1.in-addr.arpa. for 1.2.3.4.
+func reverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+
+	var b strings.Builder
+	ip16 := ip.To16()
+	for i := len(ip16) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "%x.%x.", ip16[i]&0x0f, ip16[i]>>4)
+	}
+	b.WriteString("ip6.arpa.")
+	return b.String()
+}
+
+func equalRecords(a, b []string) bool {
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
@@ -19,6 +19,9 @@ package loadbalancer
 import (
 	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,63 +30,178 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/diff"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 )
 
 const (
 	networkPrefix   string = "k8s-clusterapi"
 	kubeapiLBSuffix string = "kubeapi"
+	ipv6LBSuffix    string = "ipv6"
 )
 
 func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	loadBalancerName := getLoadBalancerName(clusterName)
 	s.logger.Info("Reconciling load balancer", "name", loadBalancerName)
 
-	// lb
-	lb, err := checkIfLbExists(s.loadbalancerClient, loadBalancerName)
+	var vipAddress string
+	if openStackCluster.Spec.DisableAPIServerFloatingIP {
+		vipAddress = openStackCluster.Spec.APIServerFixedIP
+	}
+	var lb *loadbalancers.LoadBalancer
+	var err error
+	if id := openStackCluster.Spec.APIServerLoadBalancerID; id != "" {
+		lb, err = s.getExistingLoadBalancer(id)
+	} else {
+		lb, err = s.getOrCreateLoadBalancer(openStackCluster, clusterName, loadBalancerName, openStackCluster.Status.Network.Subnet.ID, vipAddress)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Without a floating IP, the load balancer is reached on its VIP.
+	fp := &floatingips.FloatingIP{FloatingIP: lb.VipAddress}
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		floatingIPAddress := openStackCluster.Spec.ControlPlaneEndpoint.Host
+		if openStackCluster.Spec.APIServerFloatingIP != "" {
+			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
+		}
+		fp, err = s.networkingService.GetOrCreateAPIServerFloatingIP(openStackCluster, floatingIPAddress)
+		if err != nil {
+			return err
+		}
+		if fp.PortID != lb.VipPortID {
+			if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.reconcileListeners(openStackCluster, lb, loadBalancerName); err != nil {
+		return err
+	}
+
+	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
+		Name:       lb.Name,
+		ID:         lb.ID,
+		InternalIP: lb.VipAddress,
+		IP:         fp.FloatingIP,
+	}
+
+	if openStackCluster.Status.ManagedResources == nil {
+		openStackCluster.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+	managedResources := openStackCluster.Status.ManagedResources
+	managedResources.LoadBalancerIDs = nil
+	managedResources.VIPPortIDs = nil
+	if openStackCluster.Spec.APIServerLoadBalancerID == "" {
+		managedResources.LoadBalancerIDs = []string{lb.ID}
+		managedResources.VIPPortIDs = []string{lb.VipPortID}
+	}
+	if openStackCluster.Spec.APIServerFloatingIP == "" && !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		managedResources.APIServerFloatingIPID = fp.ID
+	}
+
+	if openStackCluster.Spec.APIServerLoadBalancerIPv6Subnet == nil {
+		return nil
+	}
+
+	// The IPv6 load balancer gets no floating IP, its VIP is reachable directly.
+	ipv6LoadBalancerName := getIPv6LoadBalancerName(clusterName)
+	s.logger.Info("Reconciling load balancer", "name", ipv6LoadBalancerName)
+
+	subnetID, err := s.getIPv6SubnetID(openStackCluster)
 	if err != nil {
 		return err
 	}
+	ipv6LB, err := s.getOrCreateLoadBalancer(openStackCluster, clusterName, ipv6LoadBalancerName, subnetID, "")
+	if err != nil {
+		return err
+	}
+	if err := s.reconcileListeners(openStackCluster, ipv6LB, ipv6LoadBalancerName); err != nil {
+		return err
+	}
+
+	openStackCluster.Status.APIServerLoadBalancerIPv6 = &infrav1.LoadBalancer{
+		Name:       ipv6LB.Name,
+		ID:         ipv6LB.ID,
+		InternalIP: ipv6LB.VipAddress,
+		IP:         ipv6LB.VipAddress,
+	}
+	managedResources.LoadBalancerIDs = append(managedResources.LoadBalancerIDs, ipv6LB.ID)
+	managedResources.VIPPortIDs = append(managedResources.VIPPortIDs, ipv6LB.VipPortID)
+	return nil
+}
+
+func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName, loadBalancerName, vipSubnetID, vipAddress string) (*loadbalancers.LoadBalancer, error) {
+	lb, err := checkIfLbExists(s.loadbalancerClient, loadBalancerName)
+	if err != nil {
+		return nil, err
+	}
 	if lb == nil {
 		s.logger.Info("Creating load balancer", "name", loadBalancerName)
 		lbCreateOpts := loadbalancers.CreateOpts{
 			Name:        loadBalancerName,
-			VipSubnetID: openStackCluster.Status.Network.Subnet.ID,
+			VipSubnetID: vipSubnetID,
+			VipAddress:  vipAddress,
+			Provider:    openStackCluster.Spec.APIServerLoadBalancerProvider,
+			FlavorID:    openStackCluster.Spec.APIServerLoadBalancerFlavorID,
+			// The tag of the cluster finds the load balancer on deletion even
+			// if it was never recorded in the status of the cluster.
+			Tags: append(append([]string{}, openStackCluster.Spec.Tags...), getClusterTag(clusterName)),
 		}
 
 		lb, err = loadbalancers.Create(s.loadbalancerClient, lbCreateOpts).Extract()
 		if err != nil {
 			record.Warnf(openStackCluster, "FailedCreateLoadBalancer", "Failed to create load balancer %s: %v", loadBalancerName, err)
-			return err
+			return nil, err
 		}
 		record.Eventf(openStackCluster, "SuccessfulCreateLoadBalancer", "Created load balancer %s with id %s", loadBalancerName, lb.ID)
 	}
 	if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lb.ID); err != nil {
-		return err
+		return nil, err
 	}
+	return lb, nil
+}
 
-	floatingIPAddress := openStackCluster.Spec.ControlPlaneEndpoint.Host
-	if openStackCluster.Spec.APIServerFloatingIP != "" {
-		floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
-	}
-	fp, err := s.networkingService.GetOrCreateFloatingIP(openStackCluster, floatingIPAddress)
+// getExistingLoadBalancer returns the load balancer with the given ID, which
+// is not managed by the controller, once it is active.
+func (s *Service) getExistingLoadBalancer(id string) (*loadbalancers.LoadBalancer, error) {
+	lb, err := loadbalancers.Get(s.loadbalancerClient, id).Extract()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to get load balancer %s: %v", id, err)
 	}
-	if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
+	if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lb.ID); err != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+// reconcileListeners reconciles the listeners, pools and monitors of the
+// APIServer port and the additional ports on the load balancer.
+func (s *Service) reconcileListeners(openStackCluster *infrav1.OpenStackCluster, lb *loadbalancers.LoadBalancer, loadBalancerName string) error {
+	apiServerAllowedCIDRs, err := s.getAllowedCIDRs(openStackCluster, lb)
+	if err != nil {
 		return err
 	}
 
-	// lb listener
-	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancerAdditionalPorts...)
-	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
+	listenerList := getListenerList(openStackCluster)
+	for _, l := range listenerList {
+		lbPortObjectsName := l.name(loadBalancerName)
+
+		// Only the APIServer port is restricted by APIServerAllowedCIDRs.
+		var allowedCIDRs []string
+		if l.apiServer {
+			allowedCIDRs = apiServerAllowedCIDRs
+		}
 
 		listener, err := checkIfListenerExists(s.loadbalancerClient, lbPortObjectsName)
 		if err != nil {
@@ -93,14 +211,25 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 			s.logger.Info("Creating load balancer listener", "name", lbPortObjectsName)
 			listenerCreateOpts := listeners.CreateOpts{
 				Name:           lbPortObjectsName,
-				Protocol:       "TCP",
-				ProtocolPort:   port,
+				Protocol:       listeners.Protocol(l.protocol),
+				ProtocolPort:   l.port,
 				LoadbalancerID: lb.ID,
+				AllowedCIDRs:   allowedCIDRs,
 			}
 			listener, err = listeners.Create(s.loadbalancerClient, listenerCreateOpts).Extract()
 			if err != nil {
 				return fmt.Errorf("error creating listener: %s", err)
 			}
+		} else if !equalCIDRs(listener.AllowedCIDRs, allowedCIDRs) {
+			diff.Log(s.logger, "Load balancer listener differs from desired state", allowedCIDRs, listener.AllowedCIDRs, "name", lbPortObjectsName, "field", "allowedCIDRs")
+			s.logger.Info("Updating allowed CIDRs of load balancer listener", "name", lbPortObjectsName, "allowedCIDRs", allowedCIDRs)
+			updatedCIDRs := append([]string{}, allowedCIDRs...)
+			listener, err = listeners.Update(s.loadbalancerClient, listener.ID, listeners.UpdateOpts{
+				AllowedCIDRs: &updatedCIDRs,
+			}).Extract()
+			if err != nil {
+				return fmt.Errorf("error updating listener: %s", err)
+			}
 		}
 		if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lb.ID); err != nil {
 			return err
@@ -119,8 +248,8 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 			s.logger.Info("Creating load balancer pool", "name", lbPortObjectsName)
 			poolCreateOpts := pools.CreateOpts{
 				Name:       lbPortObjectsName,
-				Protocol:   "TCP",
-				LBMethod:   pools.LBMethodRoundRobin,
+				Protocol:   pools.Protocol(l.protocol),
+				LBMethod:   lbMethod(lb),
 				ListenerID: listener.ID,
 			}
 			pool, err = pools.Create(s.loadbalancerClient, poolCreateOpts).Extract()
@@ -133,20 +262,50 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 		}
 
 		// lb monitor
+		monitorCreateOpts := monitors.CreateOpts{
+			Name:       lbPortObjectsName,
+			PoolID:     pool.ID,
+			Type:       defaultMonitorType(l.protocol),
+			Delay:      30,
+			Timeout:    5,
+			MaxRetries: 3,
+		}
+		if l.healthMonitor != nil {
+			applyHealthMonitor(&monitorCreateOpts, l.healthMonitor)
+		}
 		monitor, err := checkIfMonitorExists(s.loadbalancerClient, lbPortObjectsName)
 		if err != nil {
 			return err
 		}
+		if monitor != nil {
+			diff.Log(s.logger, "Load balancer monitor differs from desired state", monitorCreateOpts.Type, monitor.Type, "name", lbPortObjectsName, "field", "type")
+		}
+		if monitor != nil && monitor.Type != monitorCreateOpts.Type {
+			// The type of a monitor can't be updated.
+			s.logger.Info("Deleting load balancer monitor (because its type changed)", "name", lbPortObjectsName)
+			if err = monitors.Delete(s.loadbalancerClient, monitor.ID).ExtractErr(); err != nil {
+				return fmt.Errorf("error deleting monitor: %s", err)
+			}
+			if err = waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lb.ID); err != nil {
+				return err
+			}
+			monitor = nil
+		}
+		if monitor != nil && !equalMonitor(monitor, monitorCreateOpts) {
+			s.logger.Info("Updating load balancer monitor", "name", lbPortObjectsName)
+			_, err = monitors.Update(s.loadbalancerClient, monitor.ID, monitors.UpdateOpts{
+				Delay:         monitorCreateOpts.Delay,
+				Timeout:       monitorCreateOpts.Timeout,
+				MaxRetries:    monitorCreateOpts.MaxRetries,
+				URLPath:       monitorCreateOpts.URLPath,
+				ExpectedCodes: monitorCreateOpts.ExpectedCodes,
+			}).Extract()
+			if err != nil {
+				return fmt.Errorf("error updating monitor: %s", err)
+			}
+		}
 		if monitor == nil {
 			s.logger.Info("Creating load balancer monitor", "name", lbPortObjectsName)
-			monitorCreateOpts := monitors.CreateOpts{
-				Name:       lbPortObjectsName,
-				PoolID:     pool.ID,
-				Type:       "TCP",
-				Delay:      30,
-				Timeout:    5,
-				MaxRetries: 3,
-			}
 			_, err = monitors.Create(s.loadbalancerClient, monitorCreateOpts).Extract()
 			if err != nil {
 				return fmt.Errorf("error creating monitor: %s", err)
@@ -156,16 +315,179 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 			return err
 		}
 	}
+	return s.deleteListeners(lb.ID, loadBalancerName, listenerList)
+}
 
-	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
-		Name:       lb.Name,
-		ID:         lb.ID,
-		InternalIP: lb.VipAddress,
-		IP:         fp.FloatingIP,
+// DeleteListeners deletes the listeners and pools of the cluster from the
+// existing load balancer selected by APIServerLoadBalancerID, which itself is
+// not deleted.
+func (s *Service) DeleteListeners(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	lbID := openStackCluster.Spec.APIServerLoadBalancerID
+	if _, err := loadbalancers.Get(s.loadbalancerClient, lbID).Extract(); err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return s.deleteListeners(lbID, getLoadBalancerName(clusterName), nil)
+}
+
+// deleteListeners deletes the listeners and pools of the load balancer which
+// were created for the cluster, except the given ones, e.g. of ports which have
+// been removed from the additional ports of the load balancer. Listeners of
+// other clusters or services on the load balancer are kept.
+func (s *Service) deleteListeners(lbID, loadBalancerName string, keep []lbListener) error {
+	allPages, err := listeners.List(s.loadbalancerClient, listeners.ListOpts{LoadbalancerID: lbID}).AllPages()
+	if err != nil {
+		return err
+	}
+	listenerList, err := listeners.ExtractListeners(allPages)
+	if err != nil {
+		return err
+	}
+	for _, listener := range listenerList {
+		current := lbListener{protocol: listener.Protocol, port: listener.ProtocolPort}
+		if listener.Name != current.name(loadBalancerName) || containsListener(keep, current) {
+			continue
+		}
+		if listener.DefaultPoolID != "" {
+			// Deleting the pool deletes its members and monitor.
+			s.logger.Info("Deleting load balancer pool", "id", listener.DefaultPoolID)
+			if err = pools.Delete(s.loadbalancerClient, listener.DefaultPoolID).ExtractErr(); err != nil {
+				return fmt.Errorf("error deleting pool: %s", err)
+			}
+			if err = waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lbID); err != nil {
+				return err
+			}
+		}
+		s.logger.Info("Deleting load balancer listener", "name", listener.Name)
+		if err = listeners.Delete(s.loadbalancerClient, listener.ID).ExtractErr(); err != nil {
+			return fmt.Errorf("error deleting listener: %s", err)
+		}
+		if err = waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lbID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func containsListener(list []lbListener, l lbListener) bool {
+	for _, x := range list {
+		if x.protocol == l.protocol && x.port == l.port {
+			return true
+		}
+	}
+	return false
+}
+
+// lbMethod returns the load balancing algorithm of the pools of the load
+// balancer. The ovn provider only supports SOURCE_IP_PORT.
+func lbMethod(lb *loadbalancers.LoadBalancer) pools.LBMethod {
+	if lb.Provider == "ovn" {
+		return pools.LBMethodSourceIpPort
+	}
+	return pools.LBMethodRoundRobin
+}
+
+// getAllowedCIDRs returns the CIDRs the APIServer listener of the load balancer
+// accepts traffic from, or nil if the access to the APIServer is not restricted.
+// In addition to APIServerAllowedCIDRs, the subnet of the VIP and the external
+// addresses of the router are allowed, as the machines reach the APIServer from
+// them. Octavia only accepts CIDRs of the IP version of the VIP.
+func (s *Service) getAllowedCIDRs(openStackCluster *infrav1.OpenStackCluster, lb *loadbalancers.LoadBalancer) ([]string, error) {
+	if len(openStackCluster.Spec.APIServerAllowedCIDRs) == 0 {
+		return nil, nil
+	}
+
+	cidrs := append([]string{}, openStackCluster.Spec.APIServerAllowedCIDRs...)
+	vipSubnets, err := s.networkingService.GetSubnetsByFilter(&subnets.ListOpts{ID: lb.VipSubnetID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subnet of load balancer VIP: %v", err)
+	}
+	for _, subnet := range vipSubnets {
+		cidrs = append(cidrs, subnet.CIDR)
+	}
+	if router := openStackCluster.Status.Network.Router; router != nil && router.ID != "" {
+		routerIPs, err := s.networkingService.GetRouterExternalIPs(router.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get external IPs of router: %v", err)
+		}
+		cidrs = append(cidrs, routerIPs...)
+	}
+
+	vipIsIPv4 := net.ParseIP(lb.VipAddress).To4() != nil
+	var allowedCIDRs []string
+	for _, cidr := range cidrs {
+		normalized, isIPv4, err := normalizeCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid APIServer allowed CIDR %q: %v", cidr, err)
+		}
+		if isIPv4 == vipIsIPv4 && !contains(allowedCIDRs, normalized) {
+			allowedCIDRs = append(allowedCIDRs, normalized)
+		}
+	}
+	sort.Strings(allowedCIDRs)
+	return allowedCIDRs, nil
+}
+
+// normalizeCIDR returns the CIDR or single address the way Octavia stores it,
+// and whether it is an IPv4 CIDR.
+func normalizeCIDR(cidr string) (string, bool, error) {
+	if ip := net.ParseIP(cidr); ip != nil {
+		if ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", false, err
+	}
+	return ipNet.String(), ip.To4() != nil, nil
+}
+
+func equalCIDRs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// getIPv6SubnetID returns the ID of the subnet of the cluster network selected by
+// APIServerLoadBalancerIPv6Subnet.
+func (s *Service) getIPv6SubnetID(openStackCluster *infrav1.OpenStackCluster) (string, error) {
+	subnetOpts := subnets.ListOpts(*openStackCluster.Spec.APIServerLoadBalancerIPv6Subnet)
+	subnetOpts.NetworkID = openStackCluster.Status.Network.ID
+	subnetOpts.IPVersion = 6
+	subnetList, err := s.networkingService.GetSubnetsByFilter(&subnetOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to find IPv6 subnet: %v", err)
+	}
+	if len(subnetList) != 1 {
+		return "", fmt.Errorf("found %d IPv6 subnets matching the filter in network %s, expected exactly one", len(subnetList), openStackCluster.Status.Network.ID)
+	}
+	return subnetList[0].ID, nil
+}
+
 func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, clusterName, ip string) error {
 	if !util.IsControlPlaneMachine(machine) {
 		return nil
@@ -185,10 +507,42 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 	s.logger.Info("Reconciling load balancer", "name", loadBalancerName)
 
 	lbID := openStackCluster.Status.Network.APIServerLoadBalancer.ID
-	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancerAdditionalPorts...)
-	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
+	if err := s.reconcileMembers(openStackCluster, openStackMachine, loadBalancerName, lbID, ip); err != nil {
+		return err
+	}
+
+	if openStackCluster.Spec.APIServerLoadBalancerIPv6Subnet == nil {
+		return nil
+	}
+	if openStackCluster.Status.APIServerLoadBalancerIPv6 == nil {
+		return errors.New("apiServerLoadBalancerIPv6 is not yet available in openStackCluster.Status")
+	}
+	if openStackMachine.Spec.InstanceID == nil {
+		return errors.New("instance ID is not yet available in openStackMachine.Spec")
+	}
+
+	ipv6LoadBalancerName := getIPv6LoadBalancerName(clusterName)
+	s.logger.Info("Reconciling load balancer", "name", ipv6LoadBalancerName)
+
+	subnetID, err := s.getIPv6SubnetID(openStackCluster)
+	if err != nil {
+		return err
+	}
+	ipv6, err := s.networkingService.GetInstanceFixedIP(*openStackMachine.Spec.InstanceID, subnetID)
+	if err != nil {
+		return err
+	}
+	if ipv6 == "" {
+		return fmt.Errorf("instance %s has no address in IPv6 subnet %s", *openStackMachine.Spec.InstanceID, subnetID)
+	}
+	return s.reconcileMembers(openStackCluster, openStackMachine, ipv6LoadBalancerName, openStackCluster.Status.APIServerLoadBalancerIPv6.ID, ipv6)
+}
+
+// reconcileMembers ensures the machine is a member with the given IP of the pools of the load balancer.
+func (s *Service) reconcileMembers(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, loadBalancerName, lbID, ip string) error {
+	weight, backup := memberWeight(openStackMachine)
+	for _, l := range getListenerList(openStackCluster) {
+		lbPortObjectsName := l.name(loadBalancerName)
 		name := lbPortObjectsName + "-" + openStackMachine.Name
 
 		pool, err := checkIfPoolExists(s.loadbalancerClient, lbPortObjectsName)
@@ -205,10 +559,27 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 		}
 
 		if lbMember != nil {
+			diff.Log(s.logger, "Load balancer member differs from desired state", ip, lbMember.Address, "name", name, "field", "address")
 			// check if we have to recreate the LB Member
 			if lbMember.Address == ip {
-				// nothing to do return
-				return nil
+				if openStackMachine.Spec.LoadBalancerMember != nil && (lbMember.Weight != weight || lbMember.Backup != backup) {
+					diff.Log(s.logger, "Load balancer member differs from desired state", weight, lbMember.Weight, "name", name, "field", "weight")
+					s.logger.Info("Updating load balancer member", "name", name, "weight", weight, "backup", backup)
+					if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lbID); err != nil {
+						return err
+					}
+					_, err = pools.UpdateMember(s.loadbalancerClient, pool.ID, lbMember.ID, pools.UpdateMemberOpts{
+						Weight: &weight,
+						Backup: &backup,
+					}).Extract()
+					if err != nil {
+						return fmt.Errorf("error updating lbmember: %s", err)
+					}
+					if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lbID); err != nil {
+						return err
+					}
+				}
+				continue
 			}
 
 			s.logger.Info("Deleting load balancer member (because the IP of the machine changed)", "name", name)
@@ -233,9 +604,18 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 		// if we got to this point we should either create or re-create the lb member
 		lbMemberOpts := pools.CreateMemberOpts{
 			Name:         name,
-			ProtocolPort: port,
+			ProtocolPort: l.memberPort,
 			Address:      ip,
 		}
+		// Only set if configured, since not all providers support backup members.
+		if openStackMachine.Spec.LoadBalancerMember != nil {
+			lbMemberOpts.Weight = &weight
+			lbMemberOpts.Backup = &backup
+		}
+		if hm := l.healthMonitor; hm != nil && hm.Port != 0 {
+			monitorPort := hm.Port
+			lbMemberOpts.MonitorPort = &monitorPort
+		}
 
 		if err := waitForLoadBalancerActive(s.logger, s.loadbalancerClient, lbID); err != nil {
 			return err
@@ -250,30 +630,97 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 	return nil
 }
 
-func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster, loadBalancerName string) error {
-	lb, err := checkIfLbExists(s.loadbalancerClient, loadBalancerName)
-	if err != nil {
-		return err
-	}
-	if lb == nil {
-		// nothing to do
-		return nil
-	}
+// IsLoadBalancerMemberOnline reports whether the member of the machine in the
+// pool of the API server load balancer which forwards to the given port is
+// ONLINE, i.e. whether the health monitor of the load balancer reaches the port
+// on the machine. It returns an error if no listener forwards to the port.
+func (s *Service) IsLoadBalancerMemberOnline(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, clusterName string, port int) (bool, error) {
+	loadBalancerName := getLoadBalancerName(clusterName)
+	for _, l := range getListenerList(openStackCluster) {
+		if l.memberPort != port {
+			continue
+		}
+		lbPortObjectsName := l.name(loadBalancerName)
 
-	deleteOpts := loadbalancers.DeleteOpts{
-		Cascade: true,
+		pool, err := checkIfPoolExists(s.loadbalancerClient, lbPortObjectsName)
+		if err != nil {
+			return false, err
+		}
+		if pool == nil {
+			return false, nil
+		}
+		lbMember, err := checkIfLbMemberExists(s.loadbalancerClient, pool.ID, lbPortObjectsName+"-"+openStackMachine.Name)
+		if err != nil {
+			return false, err
+		}
+		return lbMember != nil && lbMember.OperatingStatus == "ONLINE", nil
 	}
-	s.logger.Info("Deleting load balancer", "name", loadBalancerName, "cascade", deleteOpts.Cascade)
-	err = loadbalancers.Delete(s.loadbalancerClient, lb.ID, deleteOpts).ExtractErr()
-	if err != nil {
-		record.Warnf(openStackCluster, "FailedDeleteLoadBalancer", "Failed to delete load balancer %s with id %s: %v", lb.Name, lb.ID, err)
-		return err
+	return false, fmt.Errorf("no listener of load balancer %s forwards to port %d", loadBalancerName, port)
+}
+
+// DeleteLoadBalancers deletes the load balancers of the cluster. They are
+// found by their names and by the tag of the cluster, so that load balancers
+// which were created before a failed reconcile could record them in the status
+// are deleted as well. The deletion cascades to the listeners, pools, monitors
+// and members, and only returns once the load balancers are gone, so that their
+// VIP ports don't block the deletion of the network. It is safe to call again
+// after an interrupted deletion. The existing load balancer selected by
+// APIServerLoadBalancerID is never deleted.
+func (s *Service) DeleteLoadBalancers(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	var lbList []loadbalancers.LoadBalancer
+	for _, listOpts := range []loadbalancers.ListOpts{
+		{Name: getLoadBalancerName(clusterName)},
+		{Name: getIPv6LoadBalancerName(clusterName)},
+		{Tags: []string{getClusterTag(clusterName)}},
+	} {
+		allPages, err := loadbalancers.List(s.loadbalancerClient, listOpts).AllPages()
+		if err != nil {
+			return err
+		}
+		lbs, err := loadbalancers.ExtractLoadBalancers(allPages)
+		if err != nil {
+			return err
+		}
+		lbList = append(lbList, lbs...)
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulDeleteLoadBalancer", "Deleted load balancer %s with id %s", lb.Name, lb.ID)
+	deleted := map[string]bool{}
+	for _, lb := range lbList {
+		if deleted[lb.ID] || lb.ID == openStackCluster.Spec.APIServerLoadBalancerID {
+			continue
+		}
+		if err := s.deleteLoadBalancer(openStackCluster, lb); err != nil {
+			return err
+		}
+		deleted[lb.ID] = true
+	}
 	return nil
 }
 
+func (s *Service) deleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster, lb loadbalancers.LoadBalancer) error {
+	if lb.ProvisioningStatus != "PENDING_DELETE" {
+		// A load balancer can't be deleted while another operation is pending.
+		if err := waitForLoadBalancerNotPending(s.logger, s.loadbalancerClient, lb.ID); err != nil {
+			if capoerrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		deleteOpts := loadbalancers.DeleteOpts{
+			Cascade: true,
+		}
+		s.logger.Info("Deleting load balancer", "name", lb.Name, "cascade", deleteOpts.Cascade)
+		err := loadbalancers.Delete(s.loadbalancerClient, lb.ID, deleteOpts).ExtractErr()
+		if err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(openStackCluster, "FailedDeleteLoadBalancer", "Failed to delete load balancer %s with id %s: %v", lb.Name, lb.ID, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulDeleteLoadBalancer", "Deleted load balancer %s with id %s", lb.Name, lb.ID)
+	}
+	return waitForLoadBalancerDeleted(s.logger, s.loadbalancerClient, lb.ID)
+}
+
 func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, clusterName string) error {
 	if openStackMachine == nil || !util.IsControlPlaneMachine(machine) {
 		return nil
@@ -283,11 +730,25 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 	s.logger.Info("Reconciling load balancer", "name", loadBalancerName)
 
 	lbID := openStackCluster.Status.Network.APIServerLoadBalancer.ID
+	if err := s.deleteMembers(openStackCluster, openStackMachine, loadBalancerName, lbID); err != nil {
+		return err
+	}
+
+	if ipv6LB := openStackCluster.Status.APIServerLoadBalancerIPv6; ipv6LB != nil {
+		ipv6LoadBalancerName := getIPv6LoadBalancerName(clusterName)
+		s.logger.Info("Reconciling load balancer", "name", ipv6LoadBalancerName)
+
+		if err := s.deleteMembers(openStackCluster, openStackMachine, ipv6LoadBalancerName, ipv6LB.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	portList := []int{int(openStackCluster.Spec.ControlPlaneEndpoint.Port)}
-	portList = append(portList, openStackCluster.Spec.APIServerLoadBalancerAdditionalPorts...)
-	for _, port := range portList {
-		lbPortObjectsName := fmt.Sprintf("%s-%d", loadBalancerName, port)
+// deleteMembers removes the machine from the pools of the load balancer.
+func (s *Service) deleteMembers(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, loadBalancerName, lbID string) error {
+	for _, l := range getListenerList(openStackCluster) {
+		lbPortObjectsName := l.name(loadBalancerName)
 		name := lbPortObjectsName + "-" + openStackMachine.Name
 
 		pool, err := checkIfPoolExists(s.loadbalancerClient, lbPortObjectsName)
@@ -324,10 +785,87 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 	return nil
 }
 
+// memberWeight returns the weight and whether the members of the machine are
+// backup members.
+func memberWeight(openStackMachine *infrav1.OpenStackMachine) (int, bool) {
+	member := openStackMachine.Spec.LoadBalancerMember
+	if member == nil {
+		return 1, false
+	}
+	weight := 1
+	if member.Weight != nil {
+		weight = *member.Weight
+	}
+	return weight, member.Backup
+}
+
+// lbListener is a listener of the load balancer with its pool and monitor.
+type lbListener struct {
+	protocol      string
+	port          int
+	memberPort    int
+	apiServer     bool
+	healthMonitor *infrav1.LoadBalancerHealthMonitor
+}
+
+// name returns the name of the listener, pool and monitor on the load
+// balancer. TCP listeners are named by their port only.
+func (l lbListener) name(loadBalancerName string) string {
+	if l.protocol == "TCP" {
+		return fmt.Sprintf("%s-%d", loadBalancerName, l.port)
+	}
+	return fmt.Sprintf("%s-%s-%d", loadBalancerName, strings.ToLower(l.protocol), l.port)
+}
+
+// getListenerList returns the listener of the APIServer port followed by the
+// listeners of the additional ports and the additional listeners.
+func getListenerList(openStackCluster *infrav1.OpenStackCluster) []lbListener {
+	port := int(openStackCluster.Spec.ControlPlaneEndpoint.Port)
+	listenerList := []lbListener{{
+		protocol:      "TCP",
+		port:          port,
+		memberPort:    port,
+		apiServer:     true,
+		healthMonitor: openStackCluster.Spec.APIServerLoadBalancerHealthMonitor,
+	}}
+	for _, port := range openStackCluster.Spec.APIServerLoadBalancerAdditionalPorts {
+		listenerList = append(listenerList, lbListener{
+			protocol:      "TCP",
+			port:          port,
+			memberPort:    port,
+			healthMonitor: openStackCluster.Spec.APIServerLoadBalancerAdditionalPortsHealthMonitor,
+		})
+	}
+	for _, listener := range openStackCluster.Spec.APIServerLoadBalancerAdditionalListeners {
+		l := lbListener{
+			protocol:   listener.Protocol,
+			port:       listener.Port,
+			memberPort: listener.TargetPort,
+		}
+		if l.protocol == "" {
+			l.protocol = "TCP"
+		}
+		if l.memberPort == 0 {
+			l.memberPort = l.port
+		}
+		listenerList = append(listenerList, l)
+	}
+	return listenerList
+}
+
+// getClusterTag returns the tag of the load balancers of the cluster.
+func getClusterTag(clusterName string) string {
+	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
+}
+
 func getLoadBalancerName(clusterName string) string {
 	return fmt.Sprintf("%s-cluster-%s-%s", networkPrefix, clusterName, kubeapiLBSuffix)
 }
 
+func getIPv6LoadBalancerName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", getLoadBalancerName(clusterName), ipv6LBSuffix)
+}
+
 func checkIfLbExists(client *gophercloud.ServiceClient, name string) (*loadbalancers.LoadBalancer, error) {
 	allPages, err := loadbalancers.List(client, loadbalancers.ListOpts{Name: name}).AllPages()
 	if err != nil {
@@ -373,6 +911,48 @@ func checkIfPoolExists(client *gophercloud.ServiceClient, name string) (*pools.P
 	return &poolList[0], nil
 }
 
+// defaultMonitorType returns the type of the health monitor of a pool of the
+// given protocol if none is configured. UDP pools can't be checked by TCP.
+func defaultMonitorType(protocol string) string {
+	if protocol == "UDP" {
+		return "UDP-CONNECT"
+	}
+	return "TCP"
+}
+
+// applyHealthMonitor sets the type, the timing and the HTTP check of a health monitor.
+func applyHealthMonitor(opts *monitors.CreateOpts, hm *infrav1.LoadBalancerHealthMonitor) {
+	if hm.Type != "" {
+		opts.Type = hm.Type
+	}
+	if hm.Delay != 0 {
+		opts.Delay = hm.Delay
+	}
+	if hm.Timeout != 0 {
+		opts.Timeout = hm.Timeout
+	}
+	if hm.MaxRetries != 0 {
+		opts.MaxRetries = hm.MaxRetries
+	}
+	if opts.Type == "HTTP" || opts.Type == "HTTPS" {
+		opts.URLPath = hm.URLPath
+		opts.ExpectedCodes = hm.ExpectedCodes
+	}
+}
+
+// equalMonitor returns whether the updatable settings of the monitor match
+// the desired ones. Octavia reports the defaults of the HTTP check, so they
+// are only compared if set.
+func equalMonitor(monitor *monitors.Monitor, opts monitors.CreateOpts) bool {
+	if monitor.Delay != opts.Delay || monitor.Timeout != opts.Timeout || monitor.MaxRetries != opts.MaxRetries {
+		return false
+	}
+	if opts.URLPath != "" && monitor.URLPath != opts.URLPath {
+		return false
+	}
+	return opts.ExpectedCodes == "" || monitor.ExpectedCodes == opts.ExpectedCodes
+}
+
 func checkIfMonitorExists(client *gophercloud.ServiceClient, name string) (*monitors.Monitor, error) {
 	allPages, err := monitors.List(client, monitors.ListOpts{Name: name}).AllPages()
 	if err != nil {
@@ -422,6 +1002,31 @@ func waitForLoadBalancerActive(logger logr.Logger, client *gophercloud.ServiceCl
 	})
 }
 
+func waitForLoadBalancerNotPending(logger logr.Logger, client *gophercloud.ServiceClient, id string) error {
+	logger.Info("Waiting for load balancer", "id", id, "targetStatus", "not PENDING")
+	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+		lb, err := loadbalancers.Get(client, id).Extract()
+		if err != nil {
+			return false, err
+		}
+		return !strings.HasPrefix(lb.ProvisioningStatus, "PENDING_"), nil
+	})
+}
+
+func waitForLoadBalancerDeleted(logger logr.Logger, client *gophercloud.ServiceClient, id string) error {
+	logger.Info("Waiting for load balancer", "id", id, "targetStatus", "DELETED")
+	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+		lb, err := loadbalancers.Get(client, id).Extract()
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return lb.ProvisioningStatus == "DELETED", nil
+	})
+}
+
 func waitForListener(logger logr.Logger, client *gophercloud.ServiceClient, id, target string) error {
 	logger.Info("Waiting for load balancer listener", "id", id, "targetStatus", target)
 	return wait.ExponentialBackoff(backoff, func() (bool, error) {
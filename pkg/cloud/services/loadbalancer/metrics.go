@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+)
+
+const (
+	metricsNamespace = "capo"
+	metricsSubsystem = "apiserver_loadbalancer"
+
+	operatingStatusOnline = "ONLINE"
+)
+
+var (
+	listenerLabels = []string{"namespace", "cluster", "loadbalancer", "listener"}
+	poolLabels     = []string{"namespace", "cluster", "loadbalancer", "pool"}
+	memberLabels   = []string{"namespace", "cluster", "loadbalancer", "pool", "member"}
+
+	listenerActiveConnections = newGaugeVec("listener_active_connections",
+		"Number of active connections of an API server load balancer listener.", listenerLabels)
+	listenerTotalConnections = newGaugeVec("listener_total_connections",
+		"Number of connections handled by an API server load balancer listener since its creation.", listenerLabels)
+	listenerBytesIn = newGaugeVec("listener_bytes_in",
+		"Number of bytes received by an API server load balancer listener since its creation.", listenerLabels)
+	listenerBytesOut = newGaugeVec("listener_bytes_out",
+		"Number of bytes sent by an API server load balancer listener since its creation.", listenerLabels)
+	listenerRequestErrors = newGaugeVec("listener_request_errors",
+		"Number of failed requests of an API server load balancer listener since its creation.", listenerLabels)
+	poolOnline = newGaugeVec("pool_online",
+		"Whether the operating status of an API server load balancer pool is ONLINE (1) or not (0).", poolLabels)
+	memberOnline = newGaugeVec("member_online",
+		"Whether the operating status of an API server load balancer member is ONLINE (1) or not (0).", memberLabels)
+
+	// collectedLabels holds the label sets set by the last collection of each
+	// cluster, so that series of removed listeners, pools and members are deleted.
+	collectedLabels      = map[string][]collectedLabel{}
+	collectedLabelsMutex sync.Mutex
+)
+
+type collectedLabel struct {
+	gaugeVec *prometheus.GaugeVec
+	labels   prometheus.Labels
+}
+
+func init() {
+	metrics.Registry.MustRegister(
+		listenerActiveConnections,
+		listenerTotalConnections,
+		listenerBytesIn,
+		listenerBytesOut,
+		listenerRequestErrors,
+		poolOnline,
+		memberOnline,
+	)
+}
+
+func newGaugeVec(name, help string, labels []string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      name,
+		Help:      help,
+	}, labels)
+}
+
+// CollectLoadBalancerMetrics pulls the listener statistics and the operating
+// status of the pools and members of the API server load balancers of the
+// cluster from Octavia and exports them as metrics.
+func (s *Service) CollectLoadBalancerMetrics(openStackCluster *infrav1.OpenStackCluster) error {
+	var lbs []*infrav1.LoadBalancer
+	if openStackCluster.Status.Network != nil && openStackCluster.Status.Network.APIServerLoadBalancer != nil {
+		lbs = append(lbs, openStackCluster.Status.Network.APIServerLoadBalancer)
+	}
+	if openStackCluster.Status.APIServerLoadBalancerIPv6 != nil {
+		lbs = append(lbs, openStackCluster.Status.APIServerLoadBalancerIPv6)
+	}
+
+	var collected []collectedLabel
+	set := func(gaugeVec *prometheus.GaugeVec, labels prometheus.Labels, value float64) {
+		gaugeVec.With(labels).Set(value)
+		collected = append(collected, collectedLabel{gaugeVec: gaugeVec, labels: labels})
+	}
+
+	for _, lb := range lbs {
+		statusTree, err := loadbalancers.GetStatuses(s.loadbalancerClient, lb.ID).Extract()
+		if err != nil {
+			return fmt.Errorf("get status tree of load balancer %s: %v", lb.Name, err)
+		}
+		if statusTree.Loadbalancer == nil {
+			continue
+		}
+
+		for _, listener := range statusTree.Loadbalancer.Listeners {
+			stats, err := listeners.GetStats(s.loadbalancerClient, listener.ID).Extract()
+			if err != nil {
+				return fmt.Errorf("get statistics of listener %s: %v", listener.Name, err)
+			}
+			labels := prometheus.Labels{"namespace": openStackCluster.Namespace, "cluster": openStackCluster.Name, "loadbalancer": lb.Name, "listener": listener.Name}
+			set(listenerActiveConnections, labels, float64(stats.ActiveConnections))
+			set(listenerTotalConnections, labels, float64(stats.TotalConnections))
+			set(listenerBytesIn, labels, float64(stats.BytesIn))
+			set(listenerBytesOut, labels, float64(stats.BytesOut))
+			set(listenerRequestErrors, labels, float64(stats.RequestErrors))
+
+			for _, pool := range listener.Pools {
+				labels := prometheus.Labels{"namespace": openStackCluster.Namespace, "cluster": openStackCluster.Name, "loadbalancer": lb.Name, "pool": pool.Name}
+				set(poolOnline, labels, online(pool.OperatingStatus))
+
+				for _, member := range pool.Members {
+					labels := prometheus.Labels{"namespace": openStackCluster.Namespace, "cluster": openStackCluster.Name, "loadbalancer": lb.Name, "pool": pool.Name, "member": member.Name}
+					set(memberOnline, labels, online(member.OperatingStatus))
+				}
+			}
+		}
+	}
+
+	collectedLabelsMutex.Lock()
+	defer collectedLabelsMutex.Unlock()
+	key := openStackCluster.Namespace + "/" + openStackCluster.Name
+	deleteStaleLabels(collectedLabels[key], collected)
+	collectedLabels[key] = collected
+	return nil
+}
+
+// DeleteLoadBalancerMetrics removes the metrics of the API server load
+// balancers of the cluster.
+func DeleteLoadBalancerMetrics(openStackCluster *infrav1.OpenStackCluster) {
+	collectedLabelsMutex.Lock()
+	defer collectedLabelsMutex.Unlock()
+	key := openStackCluster.Namespace + "/" + openStackCluster.Name
+	deleteStaleLabels(collectedLabels[key], nil)
+	delete(collectedLabels, key)
+}
+
+// deleteStaleLabels deletes the series of the previous collection which were
+// not set by the current one.
+func deleteStaleLabels(previous, current []collectedLabel) {
+	for _, p := range previous {
+		stale := true
+		for _, c := range current {
+			if p.gaugeVec == c.gaugeVec && equalLabels(p.labels, c.labels) {
+				stale = false
+				break
+			}
+		}
+		if stale {
+			p.gaugeVec.Delete(p.labels)
+		}
+	}
+}
+
+func equalLabels(a, b prometheus.Labels) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func online(operatingStatus string) float64 {
+	if operatingStatus == operatingStatusOnline {
+		return 1
+	}
+	return 0
+}
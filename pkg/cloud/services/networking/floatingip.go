@@ -17,6 +17,7 @@ limitations under the License.
 package networking
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gophercloud/gophercloud"
@@ -25,6 +26,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 )
 
 func (s *Service) GetOrCreateFloatingIP(openStackCluster *infrav1.OpenStackCluster, ip string) (*floatingips.FloatingIP, error) {
@@ -56,6 +58,42 @@ func (s *Service) GetOrCreateFloatingIP(openStackCluster *infrav1.OpenStackClust
 	return fp, nil
 }
 
+// GetOrCreateAPIServerFloatingIP returns the floating IP of the API server with
+// the address ip. The APIServerFloatingIP of the cluster is never allocated,
+// so it has to exist already.
+func (s *Service) GetOrCreateAPIServerFloatingIP(openStackCluster *infrav1.OpenStackCluster, ip string) (*floatingips.FloatingIP, error) {
+	apiServerFloatingIP := openStackCluster.Spec.APIServerFloatingIP
+	if apiServerFloatingIP == "" || (ip != "" && ip != apiServerFloatingIP) {
+		return s.GetOrCreateFloatingIP(openStackCluster, ip)
+	}
+
+	fp, err := checkIfFloatingIPExists(s.client, apiServerFloatingIP)
+	if err != nil {
+		return nil, err
+	}
+	if fp == nil {
+		record.Warnf(openStackCluster, "FailedGetFloatingIP", "Floating IP %s of the API server not found", apiServerFloatingIP)
+		return nil, fmt.Errorf("floating IP %s of the API server not found", apiServerFloatingIP)
+	}
+	return fp, nil
+}
+
+// GetBastionFloatingIP returns the floating IP of the instance of the bastion
+// of the cluster. It is provided by the user and never allocated, so it has to
+// exist already.
+func (s *Service) GetBastionFloatingIP(openStackCluster *infrav1.OpenStackCluster) (*floatingips.FloatingIP, error) {
+	bastionFloatingIP := openStackCluster.Spec.Bastion.Instance.FloatingIP
+	fp, err := checkIfFloatingIPExists(s.client, bastionFloatingIP)
+	if err != nil {
+		return nil, err
+	}
+	if fp == nil {
+		record.Warnf(openStackCluster, "FailedGetFloatingIP", "Floating IP %s of the bastion not found", bastionFloatingIP)
+		return nil, fmt.Errorf("floating IP %s of the bastion not found", bastionFloatingIP)
+	}
+	return fp, nil
+}
+
 func checkIfFloatingIPExists(client *gophercloud.ServiceClient, ip string) (*floatingips.FloatingIP, error) {
 	allPages, err := floatingips.List(client, floatingips.ListOpts{FloatingIP: ip}).AllPages()
 	if err != nil {
@@ -86,6 +124,113 @@ func (s *Service) DeleteFloatingIP(openStackCluster *infrav1.OpenStackCluster, i
 	return nil
 }
 
+// GetOrCreateMachineFloatingIP returns the floating IP of a machine with
+// AssociateFloatingIP: the one allocated for it before, the FloatingIP of its
+// spec, or a new one allocated from its FloatingIPPool.
+func (s *Service) GetOrCreateMachineFloatingIP(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (*floatingips.FloatingIP, error) {
+	if id := openStackMachine.Status.FloatingIPID; id != "" {
+		fp, err := floatingips.Get(s.client, id).Extract()
+		if err == nil {
+			return fp, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// The floating IP was released outside of the controller.
+		openStackMachine.Status.FloatingIPID = ""
+	}
+
+	if ip := openStackMachine.Spec.FloatingIP; ip != "" {
+		fp, err := checkIfFloatingIPExists(s.client, ip)
+		if err != nil {
+			return nil, err
+		}
+		if fp == nil {
+			return nil, fmt.Errorf("floating IP %s not found", ip)
+		}
+		return fp, nil
+	}
+
+	networkID, err := s.getFloatingIPPoolID(openStackCluster, openStackMachine.Spec.FloatingIPPool)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := floatingips.Create(s.client, floatingips.CreateOpts{
+		FloatingNetworkID: networkID,
+		Description:       fmt.Sprintf("Floating IP of machine %s", openStackMachine.Name),
+	}).Extract()
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedCreateFloatingIP", "Failed to create floating IP: %v", err)
+		return nil, err
+	}
+	record.Eventf(openStackMachine, "SuccessfulCreateFloatingIP", "Created floating IP %s with id %s", fp.FloatingIP, fp.ID)
+	openStackMachine.Status.FloatingIPID = fp.ID
+	return fp, nil
+}
+
+// DeleteMachineFloatingIP releases the floating IP allocated for the machine.
+// A FloatingIP of its spec is kept.
+func (s *Service) DeleteMachineFloatingIP(openStackMachine *infrav1.OpenStackMachine) error {
+	id := openStackMachine.Status.FloatingIPID
+	if id == "" {
+		return nil
+	}
+	if err := floatingips.Delete(s.client, id).ExtractErr(); err != nil && !errors.IsNotFound(err) {
+		record.Warnf(openStackMachine, "FailedDeleteFloatingIP", "Failed to delete floating IP with id %s: %v", id, err)
+		return err
+	}
+	record.Eventf(openStackMachine, "SuccessfulDeleteFloatingIP", "Deleted floating IP with id %s", id)
+	openStackMachine.Status.FloatingIPID = ""
+	return nil
+}
+
+// IsMachineFloatingIP reports whether the floating IP with the address ip is
+// the own floating IP of the machine, i.e. its FloatingIP or the floating IP
+// allocated for it.
+func (s *Service) IsMachineFloatingIP(openStackMachine *infrav1.OpenStackMachine, ip string) (bool, error) {
+	if ip == openStackMachine.Spec.FloatingIP {
+		return true, nil
+	}
+	id := openStackMachine.Status.FloatingIPID
+	if id == "" {
+		return false, nil
+	}
+	fp, err := floatingips.Get(s.client, id).Extract()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return fp.FloatingIP == ip, nil
+}
+
+// getFloatingIPPoolID returns the ID of the external network selected by the
+// name or ID of pool, or the external network of the cluster.
+func (s *Service) getFloatingIPPoolID(openStackCluster *infrav1.OpenStackCluster, pool string) (string, error) {
+	if pool == "" {
+		if openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "" {
+			return "", fmt.Errorf("no floating IP pool given and the cluster has no external network")
+		}
+		return openStackCluster.Status.ExternalNetwork.ID, nil
+	}
+
+	network, err := s.getNetworkByID(pool)
+	if err != nil {
+		return "", err
+	}
+	if network.ID == "" {
+		network, err = s.getNetworkByName(pool)
+		if err != nil {
+			return "", err
+		}
+	}
+	if network.ID == "" {
+		return "", fmt.Errorf("floating IP pool %s not found", pool)
+	}
+	return network.ID, nil
+}
+
 var backoff = wait.Backoff{
 	Steps:    10,
 	Duration: 30 * time.Second,
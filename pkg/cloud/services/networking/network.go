@@ -18,10 +18,12 @@ package networking
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/external"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/subnetpools"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/gophercloud/gophercloud/pagination"
@@ -36,6 +38,11 @@ type createOpts struct {
 	AdminStateUp        *bool  `json:"admin_state_up,omitempty"`
 	Name                string `json:"name,omitempty"`
 	PortSecurityEnabled *bool  `json:"port_security_enabled,omitempty"`
+	DNSDomain           string `json:"dns_domain,omitempty"`
+	MTU                 int    `json:"mtu,omitempty"`
+	NetworkType         string `json:"provider:network_type,omitempty"`
+	PhysicalNetwork     string `json:"provider:physical_network,omitempty"`
+	SegmentationID      int    `json:"provider:segmentation_id,omitempty"`
 }
 
 func (c createOpts) ToNetworkCreateMap() (map[string]interface{}, error) {
@@ -61,6 +68,9 @@ func (s *Service) ReconcileExternalNetwork(openStackCluster *infrav1.OpenStackCl
 	// ExternalNetworkID is not given
 	iTrue := true
 	networkListOpts := networks.ListOpts{}
+	if openStackCluster.Spec.ExternalNetwork != nil {
+		networkListOpts = networks.ListOpts(*openStackCluster.Spec.ExternalNetwork)
+	}
 	listOpts := external.ListOptsExt{
 		ListOptsBuilder: networkListOpts,
 		External:        &iTrue,
@@ -87,6 +97,9 @@ func (s *Service) ReconcileExternalNetwork(openStackCluster *infrav1.OpenStackCl
 		s.logger.Info("External network found", "network id", allNetworks[0].ID)
 		return nil
 	}
+	if openStackCluster.Spec.ExternalNetwork != nil {
+		return fmt.Errorf("found %d external networks with %+v, expected exactly one", len(allNetworks), *openStackCluster.Spec.ExternalNetwork)
+	}
 	return errors.New("too many resources")
 }
 
@@ -124,6 +137,13 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 			Name:         networkName,
 		}
 	}
+	opts.DNSDomain = openStackCluster.Spec.DNSDomain
+	opts.MTU = openStackCluster.Spec.NetworkMTU
+	if providerNetwork := openStackCluster.Spec.ProviderNetwork; providerNetwork != nil {
+		opts.NetworkType = providerNetwork.NetworkType
+		opts.PhysicalNetwork = providerNetwork.PhysicalNetwork
+		opts.SegmentationID = providerNetwork.SegmentationID
+	}
 	network, err := networks.Create(s.client, opts).Extract()
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateNetwork", "Failed to create network %s: %v", networkName, err)
@@ -131,9 +151,10 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 	}
 	record.Eventf(openStackCluster, "SuccessfulCreateNetwork", "Created network %s with id %s", networkName, network.ID)
 
-	if len(openStackCluster.Spec.Tags) > 0 {
+	tags := networkTags(openStackCluster)
+	if len(tags) > 0 {
 		_, err = attributestags.ReplaceAll(s.client, "networks", network.ID, attributestags.ReplaceAllOpts{
-			Tags: openStackCluster.Spec.Tags,
+			Tags: tags,
 		}).Extract()
 		if err != nil {
 			return err
@@ -143,7 +164,7 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 	openStackCluster.Status.Network = &infrav1.Network{
 		ID:   network.ID,
 		Name: network.Name,
-		Tags: openStackCluster.Spec.Tags,
+		Tags: tags,
 	}
 	return nil
 }
@@ -179,10 +200,16 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 	subnetName := fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
 	s.logger.Info("Reconciling subnet", "name", subnetName)
 
-	allPages, err := subnets.List(s.client, subnets.ListOpts{
+	listOpts := subnets.ListOpts{
 		NetworkID: openStackCluster.Status.Network.ID,
 		CIDR:      openStackCluster.Spec.NodeCIDR,
-	}).AllPages()
+	}
+	if openStackCluster.Spec.NodeSubnetPool != nil {
+		// The CIDR is only known once it is allocated from the subnet pool.
+		listOpts.CIDR = ""
+		listOpts.Name = subnetName
+	}
+	allPages, err := subnets.List(s.client, listOpts).AllPages()
 	if err != nil {
 		return err
 	}
@@ -199,7 +226,7 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 	var subnet *subnets.Subnet
 	if len(subnetList) == 0 {
 		var err error
-		subnet, err = createSubnet(s.client, openStackCluster, subnetName)
+		subnet, err = createSubnet(s.client, openStackCluster, subnetName, openStackCluster.Spec.NodeCIDR, openStackCluster.Spec.NodeSubnetPool)
 		if err != nil {
 			return err
 		}
@@ -214,17 +241,94 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 		CIDR: subnet.CIDR,
 		Tags: subnet.Tags,
 	}
+
+	if openStackCluster.Spec.NodeIPv6CIDR == "" {
+		return nil
+	}
+	return s.reconcileIPv6Subnet(openStackCluster, subnetName+"-ipv6")
+}
+
+// reconcileIPv6Subnet creates the IPv6 subnet of NodeIPv6CIDR in the network of
+// a dual-stack cluster.
+func (s *Service) reconcileIPv6Subnet(openStackCluster *infrav1.OpenStackCluster, subnetName string) error {
+	s.logger.Info("Reconciling subnet", "name", subnetName)
+
+	if ip, _, err := net.ParseCIDR(openStackCluster.Spec.NodeIPv6CIDR); err != nil || ip.To4() != nil {
+		return fmt.Errorf("nodeIPv6Cidr %s is not an IPv6 CIDR", openStackCluster.Spec.NodeIPv6CIDR)
+	}
+
+	allPages, err := subnets.List(s.client, subnets.ListOpts{
+		NetworkID: openStackCluster.Status.Network.ID,
+		CIDR:      openStackCluster.Spec.NodeIPv6CIDR,
+	}).AllPages()
+	if err != nil {
+		return err
+	}
+	subnetList, err := subnets.ExtractSubnets(allPages)
+	if err != nil {
+		return err
+	}
+
+	var subnet *subnets.Subnet
+	switch len(subnetList) {
+	case 0:
+		subnet, err = createSubnet(s.client, openStackCluster, subnetName, openStackCluster.Spec.NodeIPv6CIDR, nil)
+		if err != nil {
+			return err
+		}
+	case 1:
+		subnet = &subnetList[0]
+		s.logger.V(6).Info(fmt.Sprintf("Reuse existing subnet %s with id %s", subnetName, subnet.ID))
+	default:
+		return fmt.Errorf("found %d subnets with the CIDR %s, which should not happen", len(subnetList), openStackCluster.Spec.NodeIPv6CIDR)
+	}
+
+	openStackCluster.Status.Network.IPv6Subnet = &infrav1.Subnet{
+		ID:   subnet.ID,
+		Name: subnet.Name,
+		CIDR: subnet.CIDR,
+		Tags: subnet.Tags,
+	}
 	return nil
 }
 
-func createSubnet(client *gophercloud.ServiceClient, openStackCluster *infrav1.OpenStackCluster, name string) (*subnets.Subnet, error) {
+// createSubnet creates a subnet in the network of the cluster with the given
+// CIDR, or with a CIDR allocated from pool.
+func createSubnet(client *gophercloud.ServiceClient, openStackCluster *infrav1.OpenStackCluster, name, cidr string, pool *infrav1.SubnetPool) (*subnets.Subnet, error) {
 	opts := subnets.CreateOpts{
 		NetworkID:      openStackCluster.Status.Network.ID,
 		Name:           name,
-		IPVersion:      4,
-		CIDR:           openStackCluster.Spec.NodeCIDR,
+		IPVersion:      gophercloud.IPv4,
+		CIDR:           cidr,
 		DNSNameservers: openStackCluster.Spec.DNSNameservers,
 	}
+	if pool != nil {
+		subnetPool, err := getSubnetPool(client, pool)
+		if err != nil {
+			return nil, err
+		}
+		opts.SubnetPoolID = subnetPool.ID
+		opts.Prefixlen = pool.PrefixLength
+		if subnetPool.IPversion == 6 {
+			opts.IPVersion = gophercloud.IPv6
+		}
+	} else if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+		opts.IPVersion = gophercloud.IPv6
+	}
+
+	if opts.IPVersion == gophercloud.IPv6 {
+		opts.IPv6AddressMode = openStackCluster.Spec.IPv6AddressMode
+		opts.IPv6RAMode = openStackCluster.Spec.IPv6RAMode
+	} else if openStackCluster.Spec.NodeIPv6CIDR == "" && (openStackCluster.Spec.IPv6AddressMode != "" || openStackCluster.Spec.IPv6RAMode != "") {
+		return nil, fmt.Errorf("ipv6AddressMode and ipv6RaMode can only be set for an IPv6 subnet")
+	}
+	if openStackCluster.Spec.NodeSubnetDisableGateway {
+		noGateway := ""
+		opts.GatewayIP = &noGateway
+	}
+	if openStackCluster.Spec.NodeSubnetDisableDHCP {
+		opts.EnableDHCP = gophercloud.Disabled
+	}
 	subnet, err := subnets.Create(client, opts).Extract()
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedCreateSubnet", "Failed to create subnet %s: %v", name, err)
@@ -232,9 +336,9 @@ func createSubnet(client *gophercloud.ServiceClient, openStackCluster *infrav1.O
 	}
 	record.Eventf(openStackCluster, "SuccessfulCreateSubnet", "Created subnet %s with id %s", name, subnet.ID)
 
-	if len(openStackCluster.Spec.Tags) > 0 {
+	if tags := networkTags(openStackCluster); len(tags) > 0 {
 		_, err = attributestags.ReplaceAll(client, "subnets", subnet.ID, attributestags.ReplaceAllOpts{
-			Tags: openStackCluster.Spec.Tags,
+			Tags: tags,
 		}).Extract()
 		if err != nil {
 			return nil, err
@@ -244,6 +348,46 @@ func createSubnet(client *gophercloud.ServiceClient, openStackCluster *infrav1.O
 	return subnet, nil
 }
 
+// networkTags returns the tags of the Neutron resources of the cluster.
+func networkTags(openStackCluster *infrav1.OpenStackCluster) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, openStackCluster.Spec.Tags...), openStackCluster.Spec.NetworkTags...) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+	return tags
+}
+
+// getSubnetPool returns the subnet pool selected by pool.
+func getSubnetPool(client *gophercloud.ServiceClient, pool *infrav1.SubnetPool) (*subnetpools.SubnetPool, error) {
+	if pool.ID != "" {
+		return subnetpools.Get(client, pool.ID).Extract()
+	}
+
+	allPages, err := subnetpools.List(client, subnetpools.ListOpts{
+		Name:           pool.Name,
+		AddressScopeID: pool.AddressScopeID,
+	}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	subnetPoolList, err := subnetpools.ExtractSubnetPools(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(subnetPoolList) {
+	case 0:
+		return nil, fmt.Errorf("subnet pool not found")
+	case 1:
+		return &subnetPoolList[0], nil
+	}
+	return nil, fmt.Errorf("found %d subnet pools matching name %q and address scope %q, expected exactly one", len(subnetPoolList), pool.Name, pool.AddressScopeID)
+}
+
 func (s *Service) getNetworkByID(networkID string) (networks.Network, error) {
 	opts := networks.ListOpts{
 		ID: networkID,
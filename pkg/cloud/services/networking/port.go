@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+)
+
+// GetInstanceFixedIP returns the fixed IP of the instance in the given subnet,
+// or an empty string if the instance has no port in the subnet.
+func (s *Service) GetInstanceFixedIP(instanceID, subnetID string) (string, error) {
+	portList, err := s.listInstancePorts(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, port := range portList {
+		for _, fixedIP := range port.FixedIPs {
+			if fixedIP.SubnetID == subnetID {
+				return fixedIP.IPAddress, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// GetInstancePortID returns the ID of the port of the instance with the given
+// fixed IP, or an empty string if the instance has no such port.
+func (s *Service) GetInstancePortID(instanceID, ip string) (string, error) {
+	portList, err := s.listInstancePorts(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, port := range portList {
+		for _, fixedIP := range port.FixedIPs {
+			if fixedIP.IPAddress == ip {
+				return port.ID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *Service) listInstancePorts(instanceID string) ([]ports.Port, error) {
+	allPages, err := ports.List(s.client, ports.ListOpts{
+		DeviceID: instanceID,
+	}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("list ports of instance %q: %v", instanceID, err)
+	}
+	portList, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("extract ports of instance %q: %v", instanceID, err)
+	}
+	return portList, nil
+}
@@ -39,6 +39,10 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		s.logger.V(4).Info("No need to reconcile router since no subnet exists.")
 		return nil
 	}
+	if openStackCluster.Spec.NodeSubnetDisableGateway {
+		s.logger.V(4).Info("No need to reconcile router since the subnet has no gateway.")
+		return nil
+	}
 	if openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "" {
 		s.logger.V(3).Info("No need to create router, due to missing ExternalNetworkID.")
 		return nil
@@ -92,28 +96,41 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		return err
 	}
 
-	createInterface := true
-	// check all router interfaces for an existing port in our subnet.
-INTERFACE_LOOP:
-	for _, iface := range routerInterfaces {
-		for _, ip := range iface.FixedIPs {
-			if ip.SubnetID == openStackCluster.Status.Network.Subnet.ID {
-				createInterface = false
-				break INTERFACE_LOOP
-			}
+	subnetIDs := []string{openStackCluster.Status.Network.Subnet.ID}
+	if openStackCluster.Status.Network.IPv6Subnet != nil && openStackCluster.Status.Network.IPv6Subnet.ID != "" {
+		subnetIDs = append(subnetIDs, openStackCluster.Status.Network.IPv6Subnet.ID)
+	}
+	for _, subnetParam := range openStackCluster.Spec.RouterAdditionalSubnets {
+		subnetID, err := getSubnetIDByParam(s.client, subnetParam)
+		if err != nil {
+			return fmt.Errorf("failed to find additional subnet of router: %v", err)
 		}
+		subnetIDs = append(subnetIDs, subnetID)
 	}
+	for _, subnetID := range subnetIDs {
+		createInterface := true
+		// check all router interfaces for an existing port in our subnet.
+	INTERFACE_LOOP:
+		for _, iface := range routerInterfaces {
+			for _, ip := range iface.FixedIPs {
+				if ip.SubnetID == subnetID {
+					createInterface = false
+					break INTERFACE_LOOP
+				}
+			}
+		}
 
-	// ... and create a router interface for our subnet.
-	if createInterface {
-		s.logger.V(4).Info("Creating RouterInterface", "routerID", router.ID, "subnetID", openStackCluster.Status.Network.Subnet.ID)
-		routerInterface, err := routers.AddInterface(s.client, router.ID, routers.AddInterfaceOpts{
-			SubnetID: openStackCluster.Status.Network.Subnet.ID,
-		}).Extract()
-		if err != nil {
-			return fmt.Errorf("unable to create router interface: %v", err)
+		// ... and create a router interface for our subnet.
+		if createInterface {
+			s.logger.V(4).Info("Creating RouterInterface", "routerID", router.ID, "subnetID", subnetID)
+			routerInterface, err := routers.AddInterface(s.client, router.ID, routers.AddInterfaceOpts{
+				SubnetID: subnetID,
+			}).Extract()
+			if err != nil {
+				return fmt.Errorf("unable to create router interface: %v", err)
+			}
+			s.logger.V(4).Info("Created RouterInterface", "id", routerInterface.ID)
 		}
-		s.logger.V(4).Info("Created RouterInterface", "id", routerInterface.ID)
 	}
 	return nil
 }
@@ -138,9 +155,9 @@ func createRouter(client *gophercloud.ServiceClient, openStackCluster *infrav1.O
 	}
 	record.Eventf(openStackCluster, "SuccessfulCreateRouter", "Created router %s with id %s", name, router.ID)
 
-	if len(openStackCluster.Spec.Tags) > 0 {
+	if tags := networkTags(openStackCluster); len(tags) > 0 {
 		_, err = attributestags.ReplaceAll(client, "routers", router.ID, attributestags.ReplaceAllOpts{
-			Tags: openStackCluster.Spec.Tags,
+			Tags: tags,
 		}).Extract()
 		if err != nil {
 			return nil, err
@@ -150,6 +167,8 @@ func createRouter(client *gophercloud.ServiceClient, openStackCluster *infrav1.O
 	return router, nil
 }
 
+// setRouterExternalIPs sets the external fixed IPs of the gateway of the router
+// to ExternalRouterIPs, unless the gateway already uses them.
 func setRouterExternalIPs(client *gophercloud.ServiceClient, openStackCluster *infrav1.OpenStackCluster, router *routers.Router) error {
 	updateOpts := routers.UpdateOpts{
 		GatewayInfo: &routers.GatewayInfo{
@@ -158,17 +177,14 @@ func setRouterExternalIPs(client *gophercloud.ServiceClient, openStackCluster *i
 	}
 
 	for _, externalRouterIP := range openStackCluster.Spec.ExternalRouterIPs {
-		subnetID := externalRouterIP.Subnet.UUID
-		if subnetID == "" {
-			listOpts := subnets.ListOpts(externalRouterIP.Subnet.Filter)
-			subnetsByFilter, err := GetSubnetsByFilter(client, &listOpts)
-			if err != nil {
-				return err
-			}
-			if len(subnetsByFilter) != 1 {
-				return fmt.Errorf("subnetParam didn't exactly match one subnet")
-			}
-			subnetID = subnetsByFilter[0].ID
+		subnetParam := externalRouterIP.Subnet
+		if subnetParam.Filter.NetworkID == "" {
+			// Only subnets of the external network can be used by the gateway.
+			subnetParam.Filter.NetworkID = openStackCluster.Status.ExternalNetwork.ID
+		}
+		subnetID, err := getSubnetIDByParam(client, subnetParam)
+		if err != nil {
+			return err
 		}
 		updateOpts.GatewayInfo.ExternalFixedIPs = append(updateOpts.GatewayInfo.ExternalFixedIPs, routers.ExternalFixedIP{
 			IPAddress: externalRouterIP.FixedIP,
@@ -176,6 +192,10 @@ func setRouterExternalIPs(client *gophercloud.ServiceClient, openStackCluster *i
 		})
 	}
 
+	if router.GatewayInfo.NetworkID == updateOpts.GatewayInfo.NetworkID && hasExternalFixedIPs(router.GatewayInfo.ExternalFixedIPs, updateOpts.GatewayInfo.ExternalFixedIPs) {
+		return nil
+	}
+
 	if _, err := routers.Update(client, router.ID, updateOpts).Extract(); err != nil {
 		record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to update router %s with id %s: %v", router.Name, router.ID, err)
 		return err
@@ -185,6 +205,73 @@ func setRouterExternalIPs(client *gophercloud.ServiceClient, openStackCluster *i
 	return nil
 }
 
+// getSubnetIDByParam returns the ID of the subnet selected by its UUID, or by
+// a filter which has to match exactly one subnet.
+func getSubnetIDByParam(client *gophercloud.ServiceClient, subnetParam infrav1.SubnetParam) (string, error) {
+	if subnetParam.UUID != "" {
+		return subnetParam.UUID, nil
+	}
+	listOpts := subnets.ListOpts(subnetParam.Filter)
+	subnetsByFilter, err := GetSubnetsByFilter(client, &listOpts)
+	if err != nil {
+		return "", err
+	}
+	if len(subnetsByFilter) != 1 {
+		return "", fmt.Errorf("subnetParam didn't exactly match one subnet")
+	}
+	return subnetsByFilter[0].ID, nil
+}
+
+// isSubnetPort returns true if the port has an address in the subnet.
+func isSubnetPort(port ports.Port, subnet *infrav1.Subnet) bool {
+	if subnet == nil {
+		return false
+	}
+	for _, ip := range port.FixedIPs {
+		if ip.SubnetID == subnet.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasExternalFixedIPs returns true if the gateway has exactly one address for
+// each of the desired fixed IPs. A desired fixed IP without an address matches
+// any address of its subnet.
+func hasExternalFixedIPs(current, desired []routers.ExternalFixedIP) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	// Match the fixed IPs with an address first, so that those without one do
+	// not take their addresses.
+	ordered := make([]routers.ExternalFixedIP, 0, len(desired))
+	for _, d := range desired {
+		if d.IPAddress != "" {
+			ordered = append(ordered, d)
+		}
+	}
+	for _, d := range desired {
+		if d.IPAddress == "" {
+			ordered = append(ordered, d)
+		}
+	}
+	used := make([]bool, len(current))
+	for _, d := range ordered {
+		found := false
+		for i, c := range current {
+			if !used[i] && c.SubnetID == d.SubnetID && (d.IPAddress == "" || c.IPAddress == d.IPAddress) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, network *infrav1.Network) error {
 	if network.Router == nil || network.Router.ID == "" {
 		s.logger.V(4).Info("No need to delete router since no router exists.")
@@ -198,6 +285,23 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, netwo
 		s.logger.Info("Skipping router deletion because router doesn't exist", "router", network.Router.ID)
 		return nil
 	}
+	// Remove the interfaces of the IPv6 subnet and of the additional subnets
+	// before the interface of the subnet of the cluster.
+	routerInterfaces, err := s.getRouterInterfaces(network.Router.ID)
+	if err != nil {
+		return err
+	}
+	for _, iface := range routerInterfaces {
+		if iface.DeviceOwner != "network:router_interface" || isSubnetPort(iface, network.Subnet) {
+			continue
+		}
+		_, err = routers.RemoveInterface(s.client, network.Router.ID, routers.RemoveInterfaceOpts{
+			PortID: iface.ID,
+		}).Extract()
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("unable to remove router interface: %v", err)
+		}
+	}
 	if network.Subnet == nil || network.Subnet.ID == "" {
 		s.logger.V(4).Info("Skipping removing router interface since no subnet exists.")
 	} else {
@@ -223,6 +327,50 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, netwo
 	return nil
 }
 
+// GetRouterByFilter returns the router selected by the filter, which has to
+// match exactly one router.
+func (s *Service) GetRouterByFilter(filter *infrav1.RouterFilter) (*routers.Router, error) {
+	allPages, err := routers.List(s.client, routers.ListOpts{
+		ID:          filter.ID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		TenantID:    filter.TenantID,
+		ProjectID:   filter.ProjectID,
+		Tags:        filter.Tags,
+		TagsAny:     filter.TagsAny,
+		NotTags:     filter.NotTags,
+		NotTagsAny:  filter.NotTagsAny,
+	}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	routerList, err := routers.ExtractRouters(allPages)
+	if err != nil {
+		return nil, err
+	}
+	switch len(routerList) {
+	case 0:
+		return nil, fmt.Errorf("no router found with %+v", *filter)
+	case 1:
+		return &routerList[0], nil
+	}
+	return nil, fmt.Errorf("found %d routers with %+v, expected exactly one", len(routerList), *filter)
+}
+
+// GetRouterExternalIPs returns the addresses of the router in the external
+// network, which are the source addresses of the traffic it forwards there.
+func (s *Service) GetRouterExternalIPs(routerID string) ([]string, error) {
+	router, err := routers.Get(s.client, routerID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	var ips []string
+	for _, externalFixedIP := range router.GatewayInfo.ExternalFixedIPs {
+		ips = append(ips, externalFixedIP.IPAddress)
+	}
+	return ips, nil
+}
+
 func (s *Service) getRouterInterfaces(routerID string) ([]ports.Port, error) {
 	allPages, err := ports.List(s.client, ports.ListOpts{
 		DeviceID: routerID,
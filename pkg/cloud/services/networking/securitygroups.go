@@ -18,12 +18,15 @@ package networking
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/diff"
 )
 
 const (
@@ -135,16 +138,18 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 		}
 	}
 
+	egressRules, err := generateEgressRules(openStackCluster, secControlPlaneGroupID, secWorkerGroupID)
+	if err != nil {
+		return desiredSecGroups, err
+	}
+
+	apiServerRules, err := s.generateAPIServerRules(openStackCluster, secWorkerGroupID)
+	if err != nil {
+		return desiredSecGroups, err
+	}
+
 	controlPlaneRules := append(
 		[]infrav1.SecurityGroupRule{
-			{
-				Description:  "Kubernetes API",
-				Direction:    "ingress",
-				EtherType:    "IPv4",
-				PortRangeMin: 6443,
-				PortRangeMax: 6443,
-				Protocol:     "tcp",
-			},
 			{
 				Description:   "Etcd",
 				Direction:     "ingress",
@@ -174,41 +179,41 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 				Protocol:      "tcp",
 				RemoteGroupID: secWorkerGroupID,
 			},
-			{
-				Description:   "BGP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				PortRangeMin:  179,
-				PortRangeMax:  179,
-				Protocol:      "tcp",
-				RemoteGroupID: remoteGroupIDSelf,
-			},
-			{
-				Description:   "BGP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				PortRangeMin:  179,
-				PortRangeMax:  179,
-				Protocol:      "tcp",
-				RemoteGroupID: secWorkerGroupID,
-			},
-			{
-				Description:   "IP-in-IP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				Protocol:      "4",
-				RemoteGroupID: remoteGroupIDSelf,
-			},
-			{
-				Description:   "IP-in-IP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				Protocol:      "4",
-				RemoteGroupID: secWorkerGroupID,
-			},
 		},
-		defaultRules...,
+		egressRules...,
 	)
+	controlPlaneRules = append(apiServerRules, controlPlaneRules...)
+	if openStackCluster.Spec.ManagedAPIServerLoadBalancer {
+		for _, port := range openStackCluster.Spec.APIServerLoadBalancerAdditionalPorts {
+			controlPlaneRules = append(controlPlaneRules, infrav1.SecurityGroupRule{
+				Description:  fmt.Sprintf("Load balancer port %d", port),
+				Direction:    "ingress",
+				EtherType:    "IPv4",
+				PortRangeMin: port,
+				PortRangeMax: port,
+				Protocol:     "tcp",
+			})
+		}
+		for _, listener := range openStackCluster.Spec.APIServerLoadBalancerAdditionalListeners {
+			port := listener.TargetPort
+			if port == 0 {
+				port = listener.Port
+			}
+			protocol := "tcp"
+			if listener.Protocol == "UDP" {
+				protocol = "udp"
+			}
+			controlPlaneRules = append(controlPlaneRules, infrav1.SecurityGroupRule{
+				Description:  fmt.Sprintf("Load balancer port %d/%s", port, protocol),
+				Direction:    "ingress",
+				EtherType:    "IPv4",
+				PortRangeMin: port,
+				PortRangeMax: port,
+				Protocol:     protocol,
+			})
+		}
+	}
+	controlPlaneRules = append(controlPlaneRules, cniRules(openStackCluster.Spec.ManagedSecurityGroupProfile, remoteGroupIDSelf, secWorkerGroupID)...)
 
 	workerRules := append(
 		[]infrav1.SecurityGroupRule{
@@ -239,41 +244,29 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 				Protocol:      "tcp",
 				RemoteGroupID: secControlPlaneGroupID,
 			},
-			{
-				Description:   "BGP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				PortRangeMin:  179,
-				PortRangeMax:  179,
-				Protocol:      "tcp",
-				RemoteGroupID: remoteGroupIDSelf,
-			},
-			{
-				Description:   "BGP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				PortRangeMin:  179,
-				PortRangeMax:  179,
-				Protocol:      "tcp",
-				RemoteGroupID: secControlPlaneGroupID,
-			},
-			{
-				Description:   "IP-in-IP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				Protocol:      "4",
-				RemoteGroupID: remoteGroupIDSelf,
-			},
-			{
-				Description:   "IP-in-IP (calico)",
-				Direction:     "ingress",
-				EtherType:     "IPv4",
-				Protocol:      "4",
-				RemoteGroupID: secControlPlaneGroupID,
-			},
 		},
-		defaultRules...,
+		egressRules...,
 	)
+	workerRules = append(workerRules, cniRules(openStackCluster.Spec.ManagedSecurityGroupProfile, remoteGroupIDSelf, secControlPlaneGroupID)...)
+
+	managedGroupIDs := map[infrav1.MachineRole]string{
+		infrav1.MachineRoleControlPlane: secControlPlaneGroupID,
+		infrav1.MachineRoleWorker:       secWorkerGroupID,
+	}
+	allNodesRules, err := customRules(openStackCluster.Spec.AllNodesSecurityGroupRules, managedGroupIDs)
+	if err != nil {
+		return desiredSecGroups, err
+	}
+	customControlPlaneRules, err := customRules(openStackCluster.Spec.ControlPlaneSecurityGroupRules, managedGroupIDs)
+	if err != nil {
+		return desiredSecGroups, err
+	}
+	customWorkerRules, err := customRules(openStackCluster.Spec.WorkerSecurityGroupRules, managedGroupIDs)
+	if err != nil {
+		return desiredSecGroups, err
+	}
+	controlPlaneRules = append(append(controlPlaneRules, allNodesRules...), customControlPlaneRules...)
+	workerRules = append(append(workerRules, allNodesRules...), customWorkerRules...)
 
 	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
 		controlPlaneRules = append(controlPlaneRules,
@@ -315,7 +308,7 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 						Protocol:     "tcp",
 					},
 				},
-				defaultRules...,
+				egressRules...,
 			),
 		}
 	}
@@ -333,6 +326,277 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 	return desiredSecGroups, nil
 }
 
+// cniPort is a port or protocol the traffic of a CNI between the nodes uses.
+type cniPort struct {
+	description string
+	protocol    string
+	port        int
+}
+
+// cniPorts are the ports and protocols of the managed security group profiles.
+var cniPorts = map[infrav1.ManagedSecurityGroupProfile][]cniPort{
+	infrav1.ManagedSecurityGroupProfileCalico: {
+		{"BGP (calico)", "tcp", 179},
+		{"IP-in-IP (calico)", "4", 0},
+		{"VXLAN (calico)", "udp", 4789},
+	},
+	infrav1.ManagedSecurityGroupProfileCilium: {
+		{"VXLAN (cilium)", "udp", 8472},
+		{"Geneve (cilium)", "udp", 6081},
+		{"Health checks (cilium)", "tcp", 4240},
+	},
+	infrav1.ManagedSecurityGroupProfileFlannelVXLAN: {
+		{"VXLAN (flannel)", "udp", 8472},
+	},
+}
+
+// cniRules returns the rules which allow the traffic of the CNI of the profile
+// from the given security groups. Without a profile, the rules of Calico are
+// returned.
+func cniRules(profile infrav1.ManagedSecurityGroupProfile, remoteGroupIDs ...string) []infrav1.SecurityGroupRule {
+	if profile == "" {
+		profile = infrav1.ManagedSecurityGroupProfileCalico
+	}
+	var rules []infrav1.SecurityGroupRule
+	for _, p := range cniPorts[profile] {
+		for _, remoteGroupID := range remoteGroupIDs {
+			rules = append(rules, infrav1.SecurityGroupRule{
+				Description:   p.description,
+				Direction:     "ingress",
+				EtherType:     "IPv4",
+				PortRangeMin:  p.port,
+				PortRangeMax:  p.port,
+				Protocol:      p.protocol,
+				RemoteGroupID: remoteGroupID,
+			})
+		}
+	}
+	return rules
+}
+
+// generateAPIServerRules returns the rules of the managed security group of the
+// control plane which allow traffic to the APIServer. If APIServerAllowedCIDRs is
+// set, only the given CIDRs, the subnets of the cluster network, which contain
+// the members of the APIServer load balancers, and the machines of the cluster
+// are allowed.
+func (s *Service) generateAPIServerRules(openStackCluster *infrav1.OpenStackCluster, secWorkerGroupID string) ([]infrav1.SecurityGroupRule, error) {
+	apiServerRule := infrav1.SecurityGroupRule{
+		Description:  "Kubernetes API",
+		Direction:    "ingress",
+		EtherType:    "IPv4",
+		PortRangeMin: 6443,
+		PortRangeMax: 6443,
+		Protocol:     "tcp",
+	}
+	if len(openStackCluster.Spec.APIServerAllowedCIDRs) == 0 {
+		return []infrav1.SecurityGroupRule{apiServerRule}, nil
+	}
+
+	cidrs := append([]string{}, openStackCluster.Spec.APIServerAllowedCIDRs...)
+	if network := openStackCluster.Status.Network; network != nil && network.ID != "" {
+		clusterSubnets, err := GetSubnetsByFilter(s.client, &subnets.ListOpts{NetworkID: network.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, subnet := range clusterSubnets {
+			cidrs = append(cidrs, subnet.CIDR)
+		}
+	}
+
+	var apiServerRules []infrav1.SecurityGroupRule
+	for _, cidr := range cidrs {
+		prefix, etherType, err := normalizeRemoteIPPrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid APIServer allowed CIDR %q: %v", cidr, err)
+		}
+		rule := apiServerRule
+		rule.EtherType = etherType
+		rule.RemoteIPPrefix = prefix
+		if !containsRule(apiServerRules, rule) {
+			apiServerRules = append(apiServerRules, rule)
+		}
+	}
+	for _, remoteGroupID := range []string{remoteGroupIDSelf, secWorkerGroupID} {
+		for _, etherType := range []string{"IPv4", "IPv6"} {
+			rule := apiServerRule
+			rule.EtherType = etherType
+			rule.RemoteGroupID = remoteGroupID
+			apiServerRules = append(apiServerRules, rule)
+		}
+	}
+	return apiServerRules, nil
+}
+
+// containsRule returns whether the rules contain a rule equal to the given one.
+func containsRule(rules []infrav1.SecurityGroupRule, rule infrav1.SecurityGroupRule) bool {
+	for _, r := range rules {
+		if r.Equal(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEgressRules returns the egress rules of the managed security groups. Unless
+// EgressLockdown is set, all egress traffic is allowed.
+func generateEgressRules(openStackCluster *infrav1.OpenStackCluster, secControlPlaneGroupID, secWorkerGroupID string) ([]infrav1.SecurityGroupRule, error) {
+	lockdown := openStackCluster.Spec.EgressLockdown
+	if lockdown == nil {
+		return defaultRules, nil
+	}
+
+	egressRules := []infrav1.SecurityGroupRule{
+		{
+			Description:   "Cluster (control plane)",
+			Direction:     "egress",
+			EtherType:     "IPv4",
+			RemoteGroupID: secControlPlaneGroupID,
+		},
+		{
+			Description:   "Cluster (worker)",
+			Direction:     "egress",
+			EtherType:     "IPv4",
+			RemoteGroupID: secWorkerGroupID,
+		},
+		{
+			Description:    "Metadata service",
+			Direction:      "egress",
+			EtherType:      "IPv4",
+			PortRangeMin:   80,
+			PortRangeMax:   80,
+			Protocol:       "tcp",
+			RemoteIPPrefix: "169.254.169.254/32",
+		},
+	}
+
+	// The machines reach the API server through its endpoint, e.g. the floating IP
+	// of the load balancer, and not only through the security groups.
+	if endpoint := openStackCluster.Spec.ControlPlaneEndpoint; net.ParseIP(endpoint.Host) != nil {
+		rule, err := egressCIDRRule("Kubernetes API", "tcp", int(endpoint.Port), endpoint.Host)
+		if err != nil {
+			return nil, err
+		}
+		egressRules = append(egressRules, rule)
+	}
+
+	dnsCIDRs := lockdown.DNSCIDRs
+	if len(dnsCIDRs) == 0 {
+		dnsCIDRs = openStackCluster.Spec.DNSNameservers
+	}
+	destinations := []struct {
+		description string
+		protocol    string
+		port        int
+		cidrs       []string
+	}{
+		{"DNS", "udp", 53, dnsCIDRs},
+		{"DNS", "tcp", 53, dnsCIDRs},
+		{"NTP", "udp", 123, lockdown.NTPCIDRs},
+		{"Registry", "tcp", 443, lockdown.RegistryCIDRs},
+	}
+	for _, d := range destinations {
+		for _, cidr := range d.cidrs {
+			rule, err := egressCIDRRule(d.description, d.protocol, d.port, cidr)
+			if err != nil {
+				return nil, err
+			}
+			egressRules = append(egressRules, rule)
+		}
+	}
+
+	return egressRules, nil
+}
+
+// egressCIDRRule returns a rule which allows egress traffic to a port of a CIDR
+// or a single address.
+func egressCIDRRule(description, protocol string, port int, cidr string) (infrav1.SecurityGroupRule, error) {
+	prefix, etherType, err := normalizeRemoteIPPrefix(cidr)
+	if err != nil {
+		return infrav1.SecurityGroupRule{}, fmt.Errorf("invalid egress CIDR %q: %v", cidr, err)
+	}
+	return infrav1.SecurityGroupRule{
+		Description:    description,
+		Direction:      "egress",
+		EtherType:      etherType,
+		PortRangeMin:   port,
+		PortRangeMax:   port,
+		Protocol:       protocol,
+		RemoteIPPrefix: prefix,
+	}, nil
+}
+
+// normalizeRemoteIPPrefix returns the CIDR or single address the way Neutron
+// stores it as remote IP prefix of a rule, so that the rule compares equal to
+// the observed one, together with its ether type.
+func normalizeRemoteIPPrefix(cidr string) (string, string, error) {
+	if ip := net.ParseIP(cidr); ip != nil {
+		if ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", "", err
+	}
+	etherType := "IPv4"
+	if ip.To4() == nil {
+		etherType = "IPv6"
+	}
+	return ipNet.String(), etherType, nil
+}
+
+// customRules returns the rules of the managed security groups for the additional
+// rules of the cluster spec. A rule with remote managed groups results in a rule
+// for each of the groups.
+func customRules(specs []infrav1.SecurityGroupRuleSpec, managedGroupIDs map[infrav1.MachineRole]string) ([]infrav1.SecurityGroupRule, error) {
+	var customRules []infrav1.SecurityGroupRule
+	for _, spec := range specs {
+		if spec.RemoteIPPrefix != "" && len(spec.RemoteManagedGroups) > 0 {
+			return nil, fmt.Errorf("security group rule %q can't have both a remote IP prefix and remote managed groups", spec.Description)
+		}
+		rule := infrav1.SecurityGroupRule{
+			Description:  spec.Description,
+			Direction:    spec.Direction,
+			EtherType:    spec.EtherType,
+			PortRangeMin: spec.PortRangeMin,
+			PortRangeMax: spec.PortRangeMax,
+			Protocol:     spec.Protocol,
+		}
+		if rule.PortRangeMax == 0 {
+			rule.PortRangeMax = rule.PortRangeMin
+		}
+		if spec.RemoteIPPrefix != "" {
+			prefix, etherType, err := normalizeRemoteIPPrefix(spec.RemoteIPPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("security group rule %q has invalid remote IP prefix %q: %v", spec.Description, spec.RemoteIPPrefix, err)
+			}
+			rule.RemoteIPPrefix = prefix
+			if rule.EtherType == "" {
+				rule.EtherType = etherType
+			}
+		}
+		if rule.EtherType == "" {
+			rule.EtherType = "IPv4"
+		}
+		if len(spec.RemoteManagedGroups) == 0 {
+			customRules = append(customRules, rule)
+			continue
+		}
+		for _, role := range spec.RemoteManagedGroups {
+			groupID, ok := managedGroupIDs[role]
+			if !ok {
+				return nil, fmt.Errorf("security group rule %q has unknown remote managed group %q", spec.Description, role)
+			}
+			r := rule
+			r.RemoteGroupID = groupID
+			customRules = append(customRules, r)
+		}
+	}
+	return customRules, nil
+}
+
 func (s *Service) DeleteSecurityGroups(openStackCluster *infrav1.OpenStackCluster, group *infrav1.SecurityGroup) error {
 	exists, err := s.exists(group.ID)
 	if err != nil {
@@ -412,6 +676,10 @@ func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (
 		}
 	}
 
+	if len(rulesToDelete) > 0 || len(rulesToCreate) > 0 {
+		s.logger.V(diff.LogLevel).Info("Security group rules differ from desired state", "name", observed.Name, "missingRules", rulesToCreate, "unexpectedRules", rulesToDelete)
+	}
+
 	s.logger.V(4).Info("Deleting rules not needed anymore for group", "name", observed.Name, "amount", len(rulesToDelete))
 	for _, rule := range rulesToDelete {
 		s.logger.V(6).Info("Deleting rule", "ruleID", rule.ID, "groupName", observed.Name)
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// ReconcileAPIServerVIP makes sure that a port reserving the address of the
+// APIServer exists on the network of the cluster, and that the floating IP of
+// the APIServer is associated with it. The port is never bound to a server,
+// the control plane machines own its address through allowed address pairs.
+func (s *Service) ReconcileAPIServerVIP(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	portName := fmt.Sprintf("%s-cluster-%s-api-server-vip", networkPrefix, clusterName)
+	s.logger.Info("Reconciling API server VIP", "name", portName)
+
+	port, err := s.getOrCreateVIPPort(openStackCluster, portName)
+	if err != nil {
+		return err
+	}
+	if len(port.FixedIPs) == 0 {
+		return fmt.Errorf("port %s of the API server VIP has no fixed IP", port.ID)
+	}
+
+	if openStackCluster.Status.ManagedResources == nil {
+		openStackCluster.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+	managedResources := openStackCluster.Status.ManagedResources
+
+	vip := &infrav1.APIServerVIP{
+		PortID:  port.ID,
+		Address: port.FixedIPs[0].IPAddress,
+	}
+
+	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		floatingIPAddress := openStackCluster.Spec.ControlPlaneEndpoint.Host
+		if openStackCluster.Spec.APIServerFloatingIP != "" {
+			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
+		}
+		fp, err := s.GetOrCreateAPIServerFloatingIP(openStackCluster, floatingIPAddress)
+		if err != nil {
+			return err
+		}
+		if fp.PortID != port.ID {
+			if err = s.AssociateFloatingIP(openStackCluster, fp, port.ID); err != nil {
+				return err
+			}
+		}
+		vip.FloatingIP = fp.FloatingIP
+
+		if openStackCluster.Spec.APIServerFloatingIP == "" {
+			managedResources.APIServerFloatingIPID = fp.ID
+		}
+	}
+
+	openStackCluster.Status.APIServerVIP = vip
+	managedResources.VIPPortIDs = []string{port.ID}
+	return nil
+}
+
+func (s *Service) getOrCreateVIPPort(openStackCluster *infrav1.OpenStackCluster, portName string) (*ports.Port, error) {
+	networkID := openStackCluster.Status.Network.ID
+	allPages, err := ports.List(s.client, ports.ListOpts{
+		Name:      portName,
+		NetworkID: networkID,
+	}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	portList, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, err
+	}
+	switch len(portList) {
+	case 0:
+	case 1:
+		return &portList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d ports with name %q", len(portList), portName)
+	}
+
+	createOpts := ports.CreateOpts{
+		Name:        portName,
+		NetworkID:   networkID,
+		Description: fmt.Sprintf("Address of the API server of cluster %s", openStackCluster.Name),
+		FixedIPs: []ports.IP{{
+			SubnetID:  openStackCluster.Status.Network.Subnet.ID,
+			IPAddress: openStackCluster.Spec.APIServerFixedIP,
+		}},
+	}
+	port, err := ports.Create(s.client, createOpts).Extract()
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
+		return nil, err
+	}
+	record.Eventf(openStackCluster, "SuccessfulCreatePort", "Created port %s with id %s", portName, port.ID)
+
+	if tags := networkTags(openStackCluster); len(tags) > 0 {
+		_, err = attributestags.ReplaceAll(s.client, "ports", port.ID, attributestags.ReplaceAllOpts{
+			Tags: tags,
+		}).Extract()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return port, nil
+}
+
+// DeleteAPIServerVIP deletes the port reserving the address of the APIServer
+// and the floating IP allocated for it.
+func (s *Service) DeleteAPIServerVIP(openStackCluster *infrav1.OpenStackCluster) error {
+	vip := openStackCluster.Status.APIServerVIP
+	if vip == nil {
+		return nil
+	}
+
+	if vip.FloatingIP != "" && openStackCluster.Spec.APIServerFloatingIP == "" && !openStackCluster.Spec.DisableAPIServerFloatingIP {
+		if err := s.DeleteFloatingIP(openStackCluster, vip.FloatingIP); err != nil {
+			return err
+		}
+	}
+
+	if err := ports.Delete(s.client, vip.PortID).ExtractErr(); err != nil && !errors.IsNotFound(err) {
+		record.Warnf(openStackCluster, "FailedDeletePort", "Failed to delete port %s: %v", vip.PortID, err)
+		return err
+	}
+	record.Eventf(openStackCluster, "SuccessfulDeletePort", "Deleted port %s", vip.PortID)
+
+	openStackCluster.Status.APIServerVIP = nil
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servergroups
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	policySoftAntiAffinity = "soft-anti-affinity"
+
+	controlPlaneSuffix = "controlplane"
+	workerSuffix       = "worker"
+)
+
+// ServerGroupName returns the name of the managed server group of the machine.
+// Control plane machines share one server group, the machines of a machine
+// deployment share one per deployment. The policy of a server group can't be
+// changed, so groups with another policy than soft-anti-affinity carry it in
+// their name.
+func ServerGroupName(clusterName string, machine *clusterv1.Machine, policy string) string {
+	suffix := workerSuffix
+	if util.IsControlPlaneMachine(machine) {
+		suffix = controlPlaneSuffix
+	} else if deployment, ok := machine.Labels[clusterv1.MachineDeploymentLabelName]; ok {
+		suffix = fmt.Sprintf("%s-%s", workerSuffix, deployment)
+	}
+	if policy != policySoftAntiAffinity {
+		suffix = fmt.Sprintf("%s-%s", suffix, policy)
+	}
+	return fmt.Sprintf("%s%s", serverGroupPrefix(clusterName), suffix)
+}
+
+func serverGroupPrefix(clusterName string) string {
+	return fmt.Sprintf("k8s-cluster-%s-servergroup-", clusterName)
+}
+
+// GetOrCreateServerGroup returns the ID of the server group with the given name
+// and creates it with the given policy if it doesn't exist yet.
+func (s *Service) GetOrCreateServerGroup(eventObject runtime.Object, name, policy string) (string, error) {
+	serverGroupList, err := s.listServerGroups()
+	if err != nil {
+		return "", err
+	}
+	for _, serverGroup := range serverGroupList {
+		if serverGroup.Name == name {
+			return serverGroup.ID, nil
+		}
+	}
+
+	serverGroup, err := servergroups.Create(s.computeClient, servergroups.CreateOpts{
+		Name:     name,
+		Policies: []string{policy},
+	}).Extract()
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateServerGroup", "Failed to create server group %s: %v", name, err)
+		return "", err
+	}
+	record.Eventf(eventObject, "SuccessfulCreateServerGroup", "Created server group %s with policy %s and id %s", name, policy, serverGroup.ID)
+	return serverGroup.ID, nil
+}
+
+// DeleteServerGroupIfEmpty deletes the server group unless it still has members.
+func (s *Service) DeleteServerGroupIfEmpty(eventObject runtime.Object, id string) error {
+	serverGroup, err := servergroups.Get(s.computeClient, id).Extract()
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(serverGroup.Members) > 0 {
+		return nil
+	}
+	return s.deleteServerGroup(eventObject, serverGroup)
+}
+
+// DeleteServerGroups deletes all managed server groups of the cluster.
+func (s *Service) DeleteServerGroups(eventObject runtime.Object, clusterName string) error {
+	serverGroupList, err := s.listServerGroups()
+	if err != nil {
+		return err
+	}
+	prefix := serverGroupPrefix(clusterName)
+	for i := range serverGroupList {
+		if strings.HasPrefix(serverGroupList[i].Name, prefix) {
+			if err := s.deleteServerGroup(eventObject, &serverGroupList[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteServerGroup(eventObject runtime.Object, serverGroup *servergroups.ServerGroup) error {
+	err := servergroups.Delete(s.computeClient, serverGroup.ID).ExtractErr()
+	if err != nil && !capoerrors.IsNotFound(err) {
+		record.Warnf(eventObject, "FailedDeleteServerGroup", "Failed to delete server group %s with id %s: %v", serverGroup.Name, serverGroup.ID, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulDeleteServerGroup", "Deleted server group %s with id %s", serverGroup.Name, serverGroup.ID)
+	return nil
+}
+
+func (s *Service) listServerGroups() ([]servergroups.ServerGroup, error) {
+	pages, err := servergroups.List(s.computeClient).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("list server groups: %v", err)
+	}
+	serverGroupList, err := servergroups.ExtractServerGroups(pages)
+	if err != nil {
+		return nil, fmt.Errorf("extract server groups: %v", err)
+	}
+	return serverGroupList, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servergroups
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+)
+
+// computeMicroversionSoftAntiAffinity is the minimum compute API microversion
+// which supports the soft-anti-affinity policy.
+const computeMicroversionSoftAntiAffinity = "2.15"
+
+// Service interfaces with the server groups of the OpenStack compute API (Nova).
+type Service struct {
+	computeClient *gophercloud.ServiceClient
+	logger        logr.Logger
+}
+
+// NewService returns an instance of the servergroups service.
+func NewService(client *gophercloud.ProviderClient, clientOpts *clientconfig.ClientOpts, logger logr.Logger) (*Service, error) {
+	computeClient, err := openstack.NewComputeV2(client, gophercloud.EndpointOpts{
+		Region: clientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute service client: %v", err)
+	}
+	computeClient.Microversion = computeMicroversionSoftAntiAffinity
+
+	return &Service{
+		computeClient: computeClient,
+		logger:        logger,
+	}, nil
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"reflect"
+
+	"github.com/go-logr/logr"
+	kdiff "k8s.io/apimachinery/pkg/util/diff"
+)
+
+// LogLevel is the verbosity at which the differences are logged.
+const LogLevel = 6
+
+// Log logs the differences between the desired and the observed state of an
+// OpenStack resource, if they differ and the verbosity is at least LogLevel.
+func Log(logger logr.Logger, msg string, desired, observed interface{}, keysAndValues ...interface{}) {
+	logger = logger.V(LogLevel)
+	if !logger.Enabled() || reflect.DeepEqual(desired, observed) {
+		return
+	}
+	keysAndValues = append(keysAndValues, "desired", desired, "observed", observed, "diff", kdiff.ObjectReflectDiff(desired, observed))
+	logger.Info(msg, keysAndValues...)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AtLeast returns whether the microversion is equal to or newer than the
+// minimum one. An empty microversion means that the API doesn't support
+// microversions at all.
+func AtLeast(microversion, minimum string) (bool, error) {
+	if microversion == "" {
+		return false, nil
+	}
+	major, minor, err := Parse(microversion)
+	if err != nil {
+		return false, err
+	}
+	minMajor, minMinor, err := Parse(minimum)
+	if err != nil {
+		return false, err
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor), nil
+}
+
+// Parse returns the major and the minor version of a microversion like 2.52.
+func Parse(microversion string) (int, int, error) {
+	parts := strings.Split(microversion, ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid microversion %q", microversion)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion %q: %v", microversion, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion %q: %v", microversion, err)
+	}
+	return major, minor, nil
+}
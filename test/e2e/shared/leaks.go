@@ -0,0 +1,180 @@
+// +build e2e
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// FindLeakedResources returns a description of every OpenStack resource which
+// still exists after a cluster has been deleted. Resources are discovered by
+// the tag which was set on the OpenStackCluster and by the IDs recorded in its
+// status.managedResources before deletion.
+func FindLeakedResources(e2eCtx *E2EContext, tag string, managed *infrav1.ManagedResources) ([]string, error) {
+	providerClient, clientOpts, err := getProviderClient(e2eCtx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating provider client: %v", err)
+	}
+	endpointOpts := gophercloud.EndpointOpts{Region: clientOpts.RegionName}
+
+	computeClient, err := openstack.NewComputeV2(providerClient, endpointOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating compute client: %v", err)
+	}
+	// Server tags can only be filtered on with microversion 2.26 or later.
+	computeClient.Microversion = "2.26"
+
+	networkingClient, err := openstack.NewNetworkV2(providerClient, endpointOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating networking client: %v", err)
+	}
+
+	var leaked []string
+
+	serverPages, err := servers.List(computeClient, servers.ListOpts{Tags: tag}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers: %v", err)
+	}
+	serverList, err := servers.ExtractServers(serverPages)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting servers: %v", err)
+	}
+	for _, srv := range serverList {
+		leaked = append(leaked, fmt.Sprintf("server %s (%s)", srv.Name, srv.ID))
+	}
+
+	networkPages, err := networks.List(networkingClient, networks.ListOpts{Tags: tag}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("error listing networks: %v", err)
+	}
+	networkList, err := networks.ExtractNetworks(networkPages)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting networks: %v", err)
+	}
+	for _, network := range networkList {
+		leaked = append(leaked, fmt.Sprintf("network %s (%s)", network.Name, network.ID))
+	}
+
+	subnetPages, err := subnets.List(networkingClient, subnets.ListOpts{Tags: tag}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("error listing subnets: %v", err)
+	}
+	subnetList, err := subnets.ExtractSubnets(subnetPages)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting subnets: %v", err)
+	}
+	for _, subnet := range subnetList {
+		leaked = append(leaked, fmt.Sprintf("subnet %s (%s)", subnet.Name, subnet.ID))
+	}
+
+	if managed == nil {
+		return leaked, nil
+	}
+
+	check := func(kind, id string, get func(string) error) error {
+		if id == "" {
+			return nil
+		}
+		err := get(id)
+		if err == nil {
+			leaked = append(leaked, fmt.Sprintf("%s %s", kind, id))
+			return nil
+		}
+		if capoerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting %s %s: %v", kind, id, err)
+	}
+
+	getNetwork := func(id string) error { return networks.Get(networkingClient, id).Err }
+	getSubnet := func(id string) error { return subnets.Get(networkingClient, id).Err }
+	getRouter := func(id string) error { return routers.Get(networkingClient, id).Err }
+	getSecurityGroup := func(id string) error { return groups.Get(networkingClient, id).Err }
+	getPort := func(id string) error { return ports.Get(networkingClient, id).Err }
+	getFloatingIP := func(id string) error { return floatingips.Get(networkingClient, id).Err }
+	getServer := func(id string) error { return servers.Get(computeClient, id).Err }
+
+	if err := check("network", managed.NetworkID, getNetwork); err != nil {
+		return nil, err
+	}
+	for _, id := range managed.SubnetIDs {
+		if err := check("subnet", id, getSubnet); err != nil {
+			return nil, err
+		}
+	}
+	if err := check("router", managed.RouterID, getRouter); err != nil {
+		return nil, err
+	}
+	for _, id := range managed.SecurityGroupIDs {
+		if err := check("security group", id, getSecurityGroup); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range managed.VIPPortIDs {
+		if err := check("port", id, getPort); err != nil {
+			return nil, err
+		}
+	}
+	if err := check("floating IP", managed.APIServerFloatingIPID, getFloatingIP); err != nil {
+		return nil, err
+	}
+	if err := check("floating IP", managed.BastionFloatingIPID, getFloatingIP); err != nil {
+		return nil, err
+	}
+	if err := check("server", managed.BastionID, getServer); err != nil {
+		return nil, err
+	}
+
+	if len(managed.LoadBalancerIDs) > 0 {
+		loadBalancerClient, err := openstack.NewLoadBalancerV2(providerClient, endpointOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error creating load balancer client: %v", err)
+		}
+		for _, id := range managed.LoadBalancerIDs {
+			lb, err := loadbalancers.Get(loadBalancerClient, id).Extract()
+			if err != nil {
+				if capoerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("error getting load balancer %s: %v", id, err)
+			}
+			// Octavia keeps deleted load balancers around for a while.
+			if lb.ProvisioningStatus == "DELETED" {
+				continue
+			}
+			leaked = append(leaked, fmt.Sprintf("load balancer %s (%s)", lb.Name, id))
+		}
+	}
+
+	return leaked, nil
+}
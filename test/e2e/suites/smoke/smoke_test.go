@@ -0,0 +1,183 @@
+// +build e2e
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smoke
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-openstack/test/e2e/shared"
+)
+
+var _ = Describe("smoke tests", func() {
+	var (
+		namespace *corev1.Namespace
+		ctx       context.Context
+		specName  = "smoke"
+
+		// clusterName is also the tag set on all resources of the cluster.
+		clusterName      string
+		managedResources *infrav1.ManagedResources
+	)
+
+	BeforeEach(func() {
+		Expect(e2eCtx.Environment.BootstrapClusterProxy).ToNot(BeNil(), "Invalid argument. BootstrapClusterProxy can't be nil")
+		ctx = context.TODO()
+		// Setup a Namespace where to host objects for this spec and create a watcher for the namespace events.
+		namespace = shared.SetupSpecNamespace(ctx, specName, e2eCtx)
+		Expect(e2eCtx.E2EConfig).ToNot(BeNil(), "Invalid argument. e2eConfig can't be nil when calling %s spec", specName)
+		Expect(e2eCtx.E2EConfig.Variables).To(HaveKey(shared.KubernetesVersion))
+		shared.SetEnvVar("USE_CI_ARTIFACTS", "true", false)
+
+		clusterName = fmt.Sprintf("cluster-%s", namespace.Name)
+		managedResources = nil
+	})
+
+	Describe("Workload cluster (load balancer)", func() {
+		It("It should be creatable, healthy and deletable without leaks", func() {
+			managedResources = createAndValidateCluster(ctx, specName, namespace.Name, clusterName, shared.FlavorDefault)
+			Expect(managedResources.LoadBalancerIDs).ToNot(BeEmpty())
+		})
+	})
+
+	Describe("Workload cluster (without lb)", func() {
+		It("It should be creatable, healthy and deletable without leaks", func() {
+			managedResources = createAndValidateCluster(ctx, specName, namespace.Name, clusterName, shared.FlavorWithoutLB)
+			Expect(managedResources.LoadBalancerIDs).To(BeEmpty())
+		})
+	})
+
+	AfterEach(func() {
+		shared.SetEnvVar("USE_CI_ARTIFACTS", "false", false)
+		// Dumps all the resources in the spec namespace, then cleanups the cluster object and the spec namespace itself.
+		shared.DumpSpecResourcesAndCleanup(ctx, specName, namespace, e2eCtx)
+
+		if e2eCtx.Settings.SkipCleanup {
+			return
+		}
+		shared.Byf("Checking that no OpenStack resources of cluster %s were leaked", clusterName)
+		Eventually(func() ([]string, error) {
+			return shared.FindLeakedResources(e2eCtx, clusterName, managedResources)
+		}, e2eCtx.E2EConfig.GetIntervals(specName, "wait-delete-cluster")...).Should(BeEmpty())
+	})
+})
+
+// createAndValidateCluster creates a cluster with one control plane and one
+// worker machine, checks that the infrastructure reports itself healthy and
+// returns the OpenStack resources managed for the cluster.
+func createAndValidateCluster(ctx context.Context, specName, namespace, clusterName, flavor string) *infrav1.ManagedResources {
+	shared.Byf("Creating a cluster")
+	configCluster := defaultConfigCluster(clusterName, namespace)
+	configCluster.ControlPlaneMachineCount = pointer.Int64Ptr(1)
+	configCluster.WorkerMachineCount = pointer.Int64Ptr(1)
+	configCluster.Flavor = flavor
+	md := createCluster(ctx, configCluster, specName)
+
+	workerMachines := framework.GetMachinesByMachineDeployments(ctx, framework.GetMachinesByMachineDeploymentsInput{
+		Lister:            e2eCtx.Environment.BootstrapClusterProxy.GetClient(),
+		ClusterName:       clusterName,
+		Namespace:         namespace,
+		MachineDeployment: *md[0],
+	})
+	controlPlaneMachines := framework.GetControlPlaneMachinesByCluster(ctx, framework.GetControlPlaneMachinesByClusterInput{
+		Lister:      e2eCtx.Environment.BootstrapClusterProxy.GetClient(),
+		ClusterName: clusterName,
+		Namespace:   namespace,
+	})
+	Expect(len(workerMachines)).To(Equal(1))
+	Expect(len(controlPlaneMachines)).To(Equal(1))
+
+	shared.Byf("Waiting for worker nodes to be in Running phase")
+	framework.WaitForMachineStatusCheck(ctx, framework.WaitForMachineStatusCheckInput{
+		Getter:       e2eCtx.Environment.BootstrapClusterProxy.GetClient(),
+		Machine:      &workerMachines[0],
+		StatusChecks: []framework.MachineStatusCheck{framework.MachinePhaseCheck(string(clusterv1.MachinePhaseRunning))},
+	}, e2eCtx.E2EConfig.GetIntervals(specName, "wait-machine-status")...)
+
+	ctrlClient := e2eCtx.Environment.BootstrapClusterProxy.GetClient()
+
+	shared.Byf("Validating OpenStackCluster %s", clusterName)
+	openStackCluster := &infrav1.OpenStackCluster{}
+	Expect(ctrlClient.Get(ctx, apimachinerytypes.NamespacedName{Namespace: namespace, Name: clusterName}, openStackCluster)).To(Succeed())
+	Expect(openStackCluster.Status.Ready).To(BeTrue())
+	Expect(openStackCluster.Spec.ControlPlaneEndpoint.IsValid()).To(BeTrue())
+	Expect(openStackCluster.Status.Network).ToNot(BeNil())
+	Expect(openStackCluster.Status.ManagedResources).ToNot(BeNil())
+	Expect(openStackCluster.Status.ManagedResources.NetworkID).To(Equal(openStackCluster.Status.Network.ID))
+
+	shared.Byf("Validating OpenStackMachines of cluster %s", clusterName)
+	openStackMachines := &infrav1.OpenStackMachineList{}
+	Expect(ctrlClient.List(ctx, openStackMachines, crclient.InNamespace(namespace), crclient.MatchingLabels{clusterv1.ClusterLabelName: clusterName})).To(Succeed())
+	Expect(openStackMachines.Items).To(HaveLen(2))
+	for i := range openStackMachines.Items {
+		openStackMachine := &openStackMachines.Items[i]
+		Expect(openStackMachine.Status.Ready).To(BeTrue(), "OpenStackMachine %s is not ready", openStackMachine.Name)
+		Expect(conditions.IsTrue(openStackMachine, infrav1.InstanceReadyCondition)).To(BeTrue(), "OpenStackMachine %s has no ready instance", openStackMachine.Name)
+		Expect(hasInternalIP(openStackMachine.Status.Addresses)).To(BeTrue(), "OpenStackMachine %s has no internal address", openStackMachine.Name)
+	}
+
+	return openStackCluster.Status.ManagedResources.DeepCopy()
+}
+
+func hasInternalIP(addresses []corev1.NodeAddress) bool {
+	for _, address := range addresses {
+		if address.Type == corev1.NodeInternalIP && address.Address != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func createCluster(ctx context.Context, configCluster clusterctl.ConfigClusterInput, specName string) []*clusterv1.MachineDeployment {
+	result := &clusterctl.ApplyClusterTemplateAndWaitResult{}
+	clusterctl.ApplyClusterTemplateAndWait(ctx, clusterctl.ApplyClusterTemplateAndWaitInput{
+		ClusterProxy:                 e2eCtx.Environment.BootstrapClusterProxy,
+		ConfigCluster:                configCluster,
+		WaitForClusterIntervals:      e2eCtx.E2EConfig.GetIntervals(specName, "wait-cluster"),
+		WaitForControlPlaneIntervals: e2eCtx.E2EConfig.GetIntervals(specName, "wait-control-plane"),
+		WaitForMachineDeployments:    e2eCtx.E2EConfig.GetIntervals(specName, "wait-worker-nodes"),
+	}, result)
+
+	return result.MachineDeployments
+}
+
+func defaultConfigCluster(clusterName, namespace string) clusterctl.ConfigClusterInput {
+	return clusterctl.ConfigClusterInput{
+		LogFolder:              filepath.Join(e2eCtx.Settings.ArtifactFolder, "clusters", e2eCtx.Environment.BootstrapClusterProxy.GetName()),
+		ClusterctlConfigPath:   e2eCtx.Environment.ClusterctlConfigPath,
+		KubeconfigPath:         e2eCtx.Environment.BootstrapClusterProxy.GetKubeconfigPath(),
+		InfrastructureProvider: clusterctl.DefaultInfrastructureProvider,
+		Namespace:              namespace,
+		ClusterName:            clusterName,
+		KubernetesVersion:      e2eCtx.E2EConfig.GetVariable(shared.KubernetesVersion),
+	}
+}